@@ -0,0 +1,225 @@
+// Package arena plays tournaments between strategies on simulated encounters and rates them.
+//
+// Every pair of contestants plays the same series of generated boards, swapping sides between games so that neither
+// gains from moving first. Matches are simulated in parallel but rated in a fixed order, so a tournament with the
+// same configuration and seed always produces the same report.
+package arena
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/recluse-games/deviant-glados/encgen"
+	"github.com/recluse-games/deviant-glados/sim"
+	"github.com/recluse-games/deviant-glados/strategy"
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+)
+
+// Contestant a strategy playing at a difficulty.
+type Contestant struct {
+	Strategy   string
+	Difficulty string
+}
+
+// ParseContestant reads a contestant written as strategy or strategy:difficulty, such as "hunting:easy".
+func ParseContestant(spec string) (Contestant, error) {
+	parts := strings.SplitN(spec, ":", 2)
+
+	contestant := Contestant{Strategy: parts[0], Difficulty: strategy.DefaultDifficulty}
+	if len(parts) == 2 {
+		contestant.Difficulty = parts[1]
+	}
+
+	if _, err := strategy.New(contestant.Strategy, contestant.Difficulty, 0); err != nil {
+		return Contestant{}, err
+	}
+
+	return contestant, nil
+}
+
+// String returns the contestant as ParseContestant reads it.
+func (c Contestant) String() string {
+	return c.Strategy + ":" + c.Difficulty
+}
+
+// Config describes a tournament.
+type Config struct {
+	Contestants []Contestant
+	// Games is the number of games each pair of contestants plays.
+	Games int
+	// MaxTurns ends a game as a draw once this many turns have been played.
+	MaxTurns int
+	// Board describes the generated boards. Its seed is replaced for every board and its teams are always mirrored.
+	Board encgen.Config
+	// Seed seeds the boards and the contestants' play.
+	Seed int64
+	// Parallel is the number of games simulated at once, the number of CPUs when zero.
+	Parallel int
+}
+
+// game a single game of the tournament.
+type game struct {
+	first, second int
+	// swapped is set when first plays the unfriendly side.
+	swapped   bool
+	boardSeed int64
+	seed      int64
+}
+
+// outcome the result of a game, where winner is the index of the winning contestant or -1 for a draw.
+type outcome struct {
+	winner int
+	turns  int
+	// rejected and failures are indexed by first and second.
+	rejected [2]int
+	failures [2]int
+	err      error
+}
+
+// schedule lists the games of a tournament in the order they are rated, cycling through every pairing for each
+// board so that no pairing is rated all at once.
+func (c *Config) schedule() []game {
+	games := []game{}
+
+	for g := 0; g < c.Games; g++ {
+		for first := 0; first < len(c.Contestants); first++ {
+			for second := first + 1; second < len(c.Contestants); second++ {
+				games = append(games, game{
+					first:  first,
+					second: second,
+					// Consecutive games share a board with the sides swapped.
+					swapped:   g%2 == 1,
+					boardSeed: c.Seed + int64(g/2),
+					seed:      c.Seed + int64(len(games)),
+				})
+			}
+		}
+	}
+
+	return games
+}
+
+func (c *Config) player(contestant Contestant, seed int64) (sim.Player, error) {
+	s, err := strategy.New(contestant.Strategy, contestant.Difficulty, seed)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(encounterResponse *deviant.EncounterResponse) []*deviant.EncounterRequest {
+		alignment := strategy.OpposingAlignment(encounterResponse.Encounter.ActiveEntity.Alignment)
+		requests, _ := s.TakeTurn(encounterResponse, alignment)
+
+		return requests
+	}, nil
+}
+
+func (c *Config) play(g game) outcome {
+	board := c.Board
+	board.Seed = g.boardSeed
+	board.Mirror = true
+
+	encounter, err := encgen.Generate(board)
+	if err != nil {
+		return outcome{err: err}
+	}
+
+	match, err := sim.New(encounter)
+	if err != nil {
+		return outcome{err: err}
+	}
+
+	first, err := c.player(c.Contestants[g.first], g.seed)
+	if err != nil {
+		return outcome{err: err}
+	}
+
+	// The second contestant's seed is offset so that mirrored contestants do not make mirrored mistakes.
+	second, err := c.player(c.Contestants[g.second], ^g.seed)
+	if err != nil {
+		return outcome{err: err}
+	}
+
+	firstSide, secondSide := deviant.Alignment_FRIENDLY, deviant.Alignment_UNFRIENDLY
+	if g.swapped {
+		firstSide, secondSide = secondSide, firstSide
+	}
+
+	result := match.Play(map[deviant.Alignment]sim.Player{firstSide: first, secondSide: second}, c.MaxTurns)
+
+	o := outcome{
+		winner:   -1,
+		turns:    result.Turns,
+		rejected: [2]int{result.RejectedBy[firstSide], result.RejectedBy[secondSide]},
+		failures: [2]int{result.FailuresBy[firstSide], result.FailuresBy[secondSide]},
+	}
+	switch {
+	case result.Draw:
+	case result.Winner == firstSide:
+		o.winner = g.first
+	default:
+		o.winner = g.second
+	}
+
+	return o
+}
+
+// Run plays the tournament and rates the contestants.
+func Run(config *Config) (*Report, error) {
+	if len(config.Contestants) < 2 {
+		return nil, errors.New("a tournament needs at least two contestants")
+	}
+
+	if config.Games < 1 || config.MaxTurns < 1 {
+		return nil, fmt.Errorf("games and max turns must be positive, got %d and %d", config.Games, config.MaxTurns)
+	}
+
+	// Check the board configuration once rather than failing every game.
+	board := config.Board
+	board.Mirror = true
+	if _, err := encgen.Generate(board); err != nil {
+		return nil, err
+	}
+
+	parallel := config.Parallel
+	if parallel < 1 {
+		parallel = runtime.NumCPU()
+	}
+
+	games := config.schedule()
+	outcomes := make([]outcome, len(games))
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for worker := 0; worker < parallel; worker++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range indexes {
+				outcomes[i] = config.play(games[i])
+			}
+		}()
+	}
+
+	for i := range games {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	report := newReport(config.Contestants)
+	for i, g := range games {
+		if outcomes[i].err != nil {
+			return nil, fmt.Errorf("%s against %s: %v", config.Contestants[g.first], config.Contestants[g.second], outcomes[i].err)
+		}
+
+		report.record(g.first, g.second, outcomes[i].winner, outcomes[i].turns)
+		report.recordErrors(g.first, g.second, outcomes[i].rejected, outcomes[i].failures)
+	}
+
+	return report, nil
+}
@@ -0,0 +1,113 @@
+package arena
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/recluse-games/deviant-glados/encgen"
+)
+
+func TestParseContestant(t *testing.T) {
+	tests := map[string]Contestant{
+		"hunting":           {Strategy: "hunting", Difficulty: "normal"},
+		"hunting:nightmare": {Strategy: "hunting", Difficulty: "nightmare"},
+	}
+
+	for spec, expected := range tests {
+		contestant, err := ParseContestant(spec)
+		if err != nil {
+			t.Fatalf("%s: %v", spec, err)
+		}
+
+		if contestant != expected {
+			t.Errorf("%s: expected %+v but got %+v", spec, expected, contestant)
+		}
+	}
+
+	for _, spec := range []string{"missing", "hunting:impossible"} {
+		if _, err := ParseContestant(spec); err == nil {
+			t.Errorf("%s: expected an error", spec)
+		}
+	}
+}
+
+func generateConfig(parallel int) *Config {
+	return &Config{
+		Contestants: []Contestant{
+			{Strategy: "hunting", Difficulty: "easy"},
+			{Strategy: "hunting", Difficulty: "normal"},
+			{Strategy: "hunting", Difficulty: "nightmare"},
+		},
+		Games:    20,
+		MaxTurns: 100,
+		Board:    encgen.DefaultConfig(),
+		Seed:     40,
+		Parallel: parallel,
+	}
+}
+
+func TestRun(t *testing.T) {
+	report, err := Run(generateConfig(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Games != 60 {
+		t.Fatalf("expected every pair to play 20 games but got %d games", report.Games)
+	}
+
+	for _, standing := range report.Standings {
+		if standing.Games != 40 || standing.Wins+standing.Losses+standing.Draws != 40 {
+			t.Errorf("expected %s to play 40 games but got %+v", standing.Contestant, standing)
+		}
+
+		if standing.Failures != 0 {
+			t.Errorf("expected %s to never panic but it forfeited %d turns", standing.Contestant, standing.Failures)
+		}
+	}
+
+	ranked := report.Ranked()
+	if ranked[0].Contestant.Difficulty != "nightmare" || ranked[2].Contestant.Difficulty != "easy" {
+		t.Errorf("expected nightmare to rank first and easy last but got %v, %v, %v", ranked[0].Contestant, ranked[1].Contestant, ranked[2].Contestant)
+	}
+}
+
+func TestRunIsDeterministic(t *testing.T) {
+	write := func(parallel int) string {
+		report, err := Run(generateConfig(parallel))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		out := &bytes.Buffer{}
+		if err := report.Write(out); err != nil {
+			t.Fatal(err)
+		}
+
+		return out.String()
+	}
+
+	if serial, parallel := write(1), write(8); serial != parallel {
+		t.Errorf("expected the same report however many games run at once:\n%s\n%s", serial, parallel)
+	}
+}
+
+func TestRunRejectsInvalidConfigs(t *testing.T) {
+	config := generateConfig(1)
+	config.Contestants = config.Contestants[:1]
+	if _, err := Run(config); err == nil {
+		t.Error("expected an error for a single contestant")
+	}
+
+	config = generateConfig(1)
+	config.Games = 0
+	if _, err := Run(config); err == nil {
+		t.Error("expected an error for a tournament without games")
+	}
+
+	config = generateConfig(1)
+	config.Board.Friendly = 3
+	if _, err := Run(config); err == nil {
+		t.Error("expected an error for teams that cannot be mirrored")
+	}
+}
@@ -0,0 +1,162 @@
+package arena
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"text/tabwriter"
+)
+
+// Elo rating parameters.
+const (
+	InitialElo = 1500
+	EloK       = 16
+)
+
+// z95 is the normal quantile for a two-sided 95% confidence interval.
+const z95 = 1.959964
+
+// Standing a contestant's record over a tournament.
+type Standing struct {
+	Contestant Contestant
+	Games      int
+	Wins       int
+	Losses     int
+	Draws      int
+	// Turns is the total length of the contestant's games in turns.
+	Turns int
+	Elo   float64
+	// Rejected counts the contestant's requests the rules refused.
+	Rejected int
+	// Failures counts the contestant's turns forfeited because it panicked.
+	Failures int
+}
+
+// Score returns the fraction of points won, counting a draw as half a win.
+func (s *Standing) Score() float64 {
+	if s.Games == 0 {
+		return 0
+	}
+
+	return (float64(s.Wins) + float64(s.Draws)/2) / float64(s.Games)
+}
+
+// Interval returns the Wilson score interval of Score at 95% confidence.
+func (s *Standing) Interval() (float64, float64) {
+	if s.Games == 0 {
+		return 0, 1
+	}
+
+	n := float64(s.Games)
+	p := s.Score()
+
+	center := (p + z95*z95/(2*n)) / (1 + z95*z95/n)
+	margin := z95 / (1 + z95*z95/n) * math.Sqrt(p*(1-p)/n+z95*z95/(4*n*n))
+
+	return math.Max(0, center-margin), math.Min(1, center+margin)
+}
+
+// AverageTurns returns the mean length of the contestant's games in turns.
+func (s *Standing) AverageTurns() float64 {
+	if s.Games == 0 {
+		return 0
+	}
+
+	return float64(s.Turns) / float64(s.Games)
+}
+
+// Report the standings at the end of a tournament.
+type Report struct {
+	Standings []*Standing
+	Games     int
+	Draws     int
+	// Failures counts the turns forfeited by every contestant. A tournament with failures has found a bug.
+	Failures int
+}
+
+func newReport(contestants []Contestant) *Report {
+	report := &Report{}
+	for _, contestant := range contestants {
+		report.Standings = append(report.Standings, &Standing{Contestant: contestant, Elo: InitialElo})
+	}
+
+	return report
+}
+
+// expectedScore returns the score a player rated a is expected to take from a player rated b.
+func expectedScore(a float64, b float64) float64 {
+	return 1 / (1 + math.Pow(10, (b-a)/400))
+}
+
+// record rates a game between first and second, where winner is one of them or -1 for a draw.
+func (r *Report) record(first int, second int, winner int, turns int) {
+	a, b := r.Standings[first], r.Standings[second]
+
+	score := 0.5
+	switch winner {
+	case first:
+		score = 1
+		a.Wins++
+		b.Losses++
+	case second:
+		score = 0
+		a.Losses++
+		b.Wins++
+	default:
+		a.Draws++
+		b.Draws++
+		r.Draws++
+	}
+
+	change := EloK * (score - expectedScore(a.Elo, b.Elo))
+	a.Elo += change
+	b.Elo -= change
+
+	for _, standing := range []*Standing{a, b} {
+		standing.Games++
+		standing.Turns += turns
+	}
+
+	r.Games++
+}
+
+// recordErrors adds the requests refused and turns forfeited by first and second in a game.
+func (r *Report) recordErrors(first int, second int, rejected [2]int, failures [2]int) {
+	for i, index := range []int{first, second} {
+		r.Standings[index].Rejected += rejected[i]
+		r.Standings[index].Failures += failures[i]
+		r.Failures += failures[i]
+	}
+}
+
+// Ranked returns the standings from the highest rated down.
+func (r *Report) Ranked() []*Standing {
+	ranked := append([]*Standing{}, r.Standings...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Elo > ranked[j].Elo
+	})
+
+	return ranked
+}
+
+// Write prints the standings as a table.
+func (r *Report) Write(w io.Writer) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(table, "contestant\tgames\twins\tlosses\tdraws\tscore\t95%% ci\telo\tavg turns\trejected\tfailures\t\n")
+
+	for _, standing := range r.Ranked() {
+		low, high := standing.Interval()
+		fmt.Fprintf(table, "%s\t%d\t%d\t%d\t%d\t%.1f%%\t%.1f%%-%.1f%%\t%.0f\t%.1f\t%d\t%d\t\n",
+			standing.Contestant, standing.Games, standing.Wins, standing.Losses, standing.Draws,
+			100*standing.Score(), 100*low, 100*high, standing.Elo, standing.AverageTurns(), standing.Rejected, standing.Failures)
+	}
+
+	if err := table.Flush(); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(w, "\n%d games, %d drawn, %d turns failed\n", r.Games, r.Draws, r.Failures)
+
+	return err
+}
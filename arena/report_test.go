@@ -0,0 +1,77 @@
+package arena
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestRecord(t *testing.T) {
+	report := newReport([]Contestant{{"hunting", "easy"}, {"hunting", "hard"}})
+
+	report.record(0, 1, 1, 10)
+	report.record(0, 1, -1, 20)
+
+	easy, hard := report.Standings[0], report.Standings[1]
+	if easy.Losses != 1 || easy.Draws != 1 || hard.Wins != 1 || hard.Draws != 1 || report.Draws != 1 {
+		t.Fatalf("unexpected records %+v and %+v", easy, hard)
+	}
+
+	if math.Abs(easy.Elo+hard.Elo-2*InitialElo) > 1e-9 || hard.Elo <= easy.Elo {
+		t.Errorf("expected the winner to take rating from the loser but got %v and %v", easy.Elo, hard.Elo)
+	}
+
+	if hard.Score() != 0.75 || easy.AverageTurns() != 15 {
+		t.Errorf("expected a score of 0.75 and 15 turns a game but got %v and %v", hard.Score(), easy.AverageTurns())
+	}
+}
+
+func TestExpectedScore(t *testing.T) {
+	if expectedScore(1500, 1500) != 0.5 {
+		t.Error("expected equal ratings to be an even match")
+	}
+
+	if score := expectedScore(1900, 1500); math.Abs(score-10.0/11) > 1e-9 {
+		t.Errorf("expected a 400 point lead to score 10 to 1 but got %v", score)
+	}
+}
+
+func TestInterval(t *testing.T) {
+	standing := &Standing{Games: 100, Wins: 50}
+
+	low, high := standing.Interval()
+	if math.Abs(low-0.404) > 0.001 || math.Abs(high-0.596) > 0.001 {
+		t.Errorf("expected the interval 0.404-0.596 but got %v-%v", low, high)
+	}
+
+	low, high = (&Standing{Games: 10, Wins: 10}).Interval()
+	if high > 1 || high < 0.999 || low < 0.7 || low > 0.75 {
+		t.Errorf("expected a perfect record to stay within bounds but got %v-%v", low, high)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	report := newReport([]Contestant{{"hunting", "easy"}, {"hunting", "hard"}})
+	report.record(0, 1, 1, 10)
+	report.recordErrors(0, 1, [2]int{3, 0}, [2]int{1, 0})
+
+	easy := report.Standings[0]
+	if easy.Rejected != 3 || easy.Failures != 1 || report.Standings[1].Rejected != 0 || report.Failures != 1 {
+		t.Fatalf("expected the errors to be charged to the easy contestant, got %+v and %+v", easy, report.Standings[1])
+	}
+
+	out := &bytes.Buffer{}
+	if err := report.Write(out); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(out.String(), "\n")
+	if !strings.Contains(lines[1], "hunting:hard") || !strings.Contains(lines[2], "hunting:easy") {
+		t.Errorf("expected the winner to be listed first:\n%s", out)
+	}
+
+	if !strings.Contains(out.String(), "1 games, 0 drawn, 1 turns failed") {
+		t.Errorf("expected a summary line:\n%s", out)
+	}
+}
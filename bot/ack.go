@@ -0,0 +1,69 @@
+package bot
+
+import (
+	"fmt"
+
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+)
+
+// entityPosition finds an entity on the board by id.
+func entityPosition(encounter *deviant.Encounter, id string) (int32, int32, bool) {
+	if encounter.Board == nil || encounter.Board.Entities == nil {
+		return 0, 0, false
+	}
+
+	for x, row := range encounter.Board.Entities.Entities {
+		for y, entity := range row.Entities {
+			if entity.Id == id {
+				return int32(x), int32(y), true
+			}
+		}
+	}
+
+	return 0, 0, false
+}
+
+func handContains(entity *deviant.Entity, instanceID string) bool {
+	if entity == nil || entity.Hand == nil {
+		return false
+	}
+
+	for _, card := range entity.Hand.Cards {
+		if card.InstanceId == instanceID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// verifyAck checks that the snapshot received after sending a request shows the effect the request should have
+// had. Requests without a visible effect on the board, such as targeting, are always accepted.
+func verifyAck(request *deviant.EncounterRequest, before *deviant.Encounter, after *deviant.Encounter) error {
+	switch {
+	case request.EntityMoveAction != nil:
+		if before.ActiveEntity == nil {
+			return nil
+		}
+
+		move := request.EntityMoveAction
+		x, y, ok := entityPosition(after, before.ActiveEntity.Id)
+		if !ok {
+			return fmt.Errorf("entity %s is no longer on the board", before.ActiveEntity.Id)
+		}
+
+		if x != move.FinalXPosition || y != move.FinalYPosition {
+			return fmt.Errorf("entity %s is at (%d, %d) rather than (%d, %d)", before.ActiveEntity.Id, x, y, move.FinalXPosition, move.FinalYPosition)
+		}
+	case request.EntityPlayAction != nil:
+		if handContains(after.ActiveEntity, request.EntityPlayAction.CardId) {
+			return fmt.Errorf("card %s is still in hand", request.EntityPlayAction.CardId)
+		}
+	case request.EntityActionName == deviant.EntityActionNames_CHANGE_PHASE:
+		if KeyOf(after) == KeyOf(before) {
+			return fmt.Errorf("turn is still in %v", after.Turn.GetPhase())
+		}
+	}
+
+	return nil
+}
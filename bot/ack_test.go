@@ -0,0 +1,296 @@
+package bot
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/recluse-games/deviant-glados/logging"
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+	"google.golang.org/protobuf/proto"
+)
+
+// generateLine builds a one row board with player 0001's entity at column y holding a single card.
+func generateLine(y int, hand ...string) *deviant.EncounterResponse {
+	hero := &deviant.Entity{Id: "0001", OwnerId: "0001", Hand: &deviant.Hand{}}
+	for _, instanceID := range hand {
+		hero.Hand.Cards = append(hero.Hand.Cards, &deviant.Card{InstanceId: instanceID})
+	}
+
+	row := &deviant.EntitiesRow{Entities: []*deviant.Entity{{}, {}, {}, {}}}
+	row.Entities[y] = hero
+
+	return &deviant.EncounterResponse{
+		Encounter: &deviant.Encounter{
+			Id:           "encounter_0000",
+			Turn:         &deviant.Turn{Id: "turn_0000"},
+			ActiveEntity: hero,
+			Board: &deviant.Board{
+				Entities: &deviant.Entities{Entities: []*deviant.EntitiesRow{row}},
+			},
+		},
+	}
+}
+
+func moveTo(y int32) *deviant.EncounterRequest {
+	return &deviant.EncounterRequest{
+		EntityActionName: deviant.EntityActionNames_MOVE,
+		EntityMoveAction: &deviant.EntityMoveAction{FinalXPosition: 0, FinalYPosition: y},
+	}
+}
+
+func play(instanceID string) *deviant.EncounterRequest {
+	return &deviant.EncounterRequest{
+		EntityPlayAction: &deviant.EntityPlayAction{CardId: instanceID},
+	}
+}
+
+func endTurn() *deviant.EncounterRequest {
+	return &deviant.EncounterRequest{EntityActionName: deviant.EntityActionNames_CHANGE_PHASE}
+}
+
+func TestVerifyAck(t *testing.T) {
+	before := generateLine(0, "card_0000").Encounter
+
+	cases := []struct {
+		name     string
+		request  *deviant.EncounterRequest
+		after    *deviant.Encounter
+		accepted bool
+	}{
+		{"move accepted", moveTo(2), generateLine(2, "card_0000").Encounter, true},
+		{"move rejected", moveTo(2), generateLine(0, "card_0000").Encounter, false},
+		{"play accepted", play("card_0000"), generateLine(0).Encounter, true},
+		{"play rejected", play("card_0000"), generateLine(0, "card_0000").Encounter, false},
+		{"target", &deviant.EncounterRequest{EntityTargetAction: &deviant.EntityTargetAction{}}, before, true},
+		{"end turn rejected", endTurn(), before, false},
+	}
+
+	for _, c := range cases {
+		if err := verifyAck(c.request, before, c.after); (err == nil) != c.accepted {
+			t.Errorf("%s: expected accepted %v, got %v", c.name, c.accepted, err)
+		}
+	}
+}
+
+// lineServer applies requests to a one row board, refusing moves to blocked columns.
+type lineServer struct {
+	mu       sync.Mutex
+	runtime  *Runtime
+	state    *deviant.EncounterResponse
+	blocked  map[int32]bool
+	silent   bool
+	requests []*deviant.EncounterRequest
+}
+
+func (s *lineServer) send(request *deviant.EncounterRequest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, request)
+	next := proto.Clone(s.state).(*deviant.EncounterResponse)
+	hero := next.Encounter.ActiveEntity
+
+	switch {
+	case request.EntityMoveAction != nil:
+		if !s.blocked[request.EntityMoveAction.FinalYPosition] {
+			cards := []string{}
+			for _, card := range hero.Hand.Cards {
+				cards = append(cards, card.InstanceId)
+			}
+
+			next = generateLine(int(request.EntityMoveAction.FinalYPosition), cards...)
+		}
+	case request.EntityPlayAction != nil:
+		hero.Hand.Cards = nil
+	case request.EntityActionName == deviant.EntityActionNames_CHANGE_PHASE:
+		next.Encounter.Turn.Id = "turn_0001"
+		next.Encounter.ActiveEntity = &deviant.Entity{Id: "0002", OwnerId: "0002"}
+	}
+
+	s.state = next
+	if !s.silent {
+		go s.runtime.Deliver(next)
+	}
+
+	return nil
+}
+
+func (s *lineServer) sent() []*deviant.EncounterRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]*deviant.EncounterRequest{}, s.requests...)
+}
+
+// linePlanner moves to the rightmost free column it has not tried, plays its card, then ends the turn.
+func linePlanner(tried map[int32]bool) Planner {
+	return func(ctx context.Context, encounterResponse *deviant.EncounterResponse) (*Plan, error) {
+		target := int32(3)
+		for tried[target] {
+			target--
+		}
+		tried[target] = true
+
+		return &Plan{Requests: []*deviant.EncounterRequest{moveTo(target), play("card_0000"), endTurn()}}, nil
+	}
+}
+
+func TestRuntimeReplansRejectedMove(t *testing.T) {
+	server := &lineServer{
+		state:   generateLine(0, "card_0000"),
+		blocked: map[int32]bool{3: true},
+	}
+
+	runtime := NewRuntime("0001", linePlanner(map[int32]bool{}), server.send)
+	runtime.AckTimeout = time.Second
+	server.runtime = runtime
+
+	var mu sync.Mutex
+	plans, turns := 0, 0
+	rejected := []*deviant.EncounterRequest{}
+	runtime.OnPlan = func(*Plan, time.Duration) {
+		mu.Lock()
+		plans++
+		mu.Unlock()
+	}
+	runtime.OnReject = func(request *deviant.EncounterRequest, err error) {
+		mu.Lock()
+		rejected = append(rejected, request)
+		mu.Unlock()
+	}
+	runtime.OnTurn = func(TurnKey) {
+		mu.Lock()
+		turns++
+		mu.Unlock()
+	}
+
+	stop := startRuntime(runtime)
+	defer stop()
+
+	runtime.Deliver(server.state)
+	waitFor(t, func() bool { return len(server.sent()) == 4 })
+	waitFor(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return turns == 1
+	})
+
+	mu.Lock()
+	if plans != 2 || len(rejected) != 1 || rejected[0].EntityMoveAction.FinalYPosition != 3 {
+		t.Errorf("expected two plans and the blocked move to be reported, got %d plans and %v", plans, rejected)
+	}
+	mu.Unlock()
+
+	requests := server.sent()
+	if requests[0].EntityMoveAction.FinalYPosition != 3 || requests[1].EntityMoveAction.FinalYPosition != 2 {
+		t.Fatalf("expected the rejected move to be replanned, got %v", requests)
+	}
+
+	if requests[2].EntityPlayAction == nil || requests[3].EntityActionName != deviant.EntityActionNames_CHANGE_PHASE {
+		t.Fatalf("expected the rest of the replanned turn to follow, got %v", requests)
+	}
+}
+
+func TestRuntimeEndsTurnAfterTooManyReplans(t *testing.T) {
+	server := &lineServer{
+		state:  generateLine(0, "card_0000"),
+		silent: true,
+	}
+
+	runtime := NewRuntime("0001", linePlanner(map[int32]bool{}), server.send)
+	runtime.AckTimeout = 5 * time.Millisecond
+	runtime.MaxReplans = 2
+	server.runtime = runtime
+
+	stop := startRuntime(runtime)
+	defer stop()
+
+	runtime.Deliver(server.state)
+	waitFor(t, func() bool { return len(server.sent()) == 4 })
+	time.Sleep(20 * time.Millisecond)
+
+	requests := server.sent()
+	if len(requests) != 4 {
+		t.Fatalf("expected three unacknowledged moves and an end turn, got %v", requests)
+	}
+
+	if requests[3].EntityActionName != deviant.EntityActionNames_CHANGE_PHASE {
+		t.Fatalf("expected the turn to be ended after giving up, got %v", requests[3])
+	}
+}
+
+// lateLogger delivers a late acknowledgement just as the runtime gives up waiting for it.
+type lateLogger struct {
+	logging.Logger
+
+	once    sync.Once
+	deliver func()
+}
+
+func (l *lateLogger) Warn(message string, fields ...logging.Field) {
+	l.once.Do(l.deliver)
+}
+
+func (l *lateLogger) With(...logging.Field) logging.Logger {
+	return l
+}
+
+func TestRuntimeReplansFromLateAcknowledgement(t *testing.T) {
+	server := &lineServer{
+		state:  generateLine(0, "card_0000"),
+		silent: true,
+	}
+
+	var mu sync.Mutex
+	columns := []int{}
+
+	planner := func(ctx context.Context, encounterResponse *deviant.EncounterResponse) (*Plan, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		for y, entity := range encounterResponse.Encounter.Board.Entities.Entities[0].Entities {
+			if entity.Id == "0001" {
+				columns = append(columns, y)
+			}
+		}
+
+		return &Plan{Requests: []*deviant.EncounterRequest{moveTo(3), endTurn()}}, nil
+	}
+
+	runtime := NewRuntime("0001", planner, server.send)
+	runtime.AckTimeout = 5 * time.Millisecond
+	runtime.MaxReplans = 1
+	runtime.Logger = &lateLogger{Logger: logging.Nop(), deliver: func() {
+		server.mu.Lock()
+		defer server.mu.Unlock()
+
+		runtime.Deliver(server.state)
+	}}
+	server.runtime = runtime
+
+	stop := startRuntime(runtime)
+	defer stop()
+
+	runtime.Deliver(server.state)
+	waitFor(t, func() bool {
+		requests := server.sent()
+
+		return len(requests) > 0 && requests[len(requests)-1].EntityActionName == deviant.EntityActionNames_CHANGE_PHASE
+	})
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(columns) < 2 {
+		t.Fatalf("expected the turn to be replanned, got %d plans", len(columns))
+	}
+
+	for _, column := range columns[1:] {
+		if column != 3 {
+			t.Fatalf("expected every replan to see the acknowledged move, got boards with the entity at %v", columns)
+		}
+	}
+}
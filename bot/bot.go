@@ -0,0 +1,300 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/recluse-games/deviant-glados/client"
+	"github.com/recluse-games/deviant-glados/hunting"
+	"github.com/recluse-games/deviant-glados/logging"
+	"github.com/recluse-games/deviant-glados/metrics"
+	"github.com/recluse-games/deviant-glados/record"
+	"github.com/recluse-games/deviant-glados/strategy"
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+)
+
+// Config describes a single bot.
+type Config struct {
+	PlayerID string
+	// Open opens streams to the encounter server, such as client.Redial or client.Shared.
+	Open client.OpenFunc
+	// Backoff spaces out reconnection attempts, client.DefaultBackoff when zero.
+	Backoff client.Backoff
+	// Plan plans the bot's turns, replacing Strategy, Hunt and OnTrace when it is not nil.
+	Plan Planner
+	// Strategy plans the bot's turns, the default strategy at the default difficulty when nil.
+	Strategy strategy.Strategy
+	// Hunt fixes the alignment the bot hunts, otherwise it hunts whoever opposes the active entity.
+	Hunt *deviant.Alignment
+	// Pacer decides the pause before each request, NoDelay when nil.
+	Pacer Pacer
+	// Seed seeds the pacer's randomness when it is a ThinkTime without its own source.
+	Seed int64
+	// AckTimeout is how long to wait for each request to be acknowledged, zero to send turns blindly.
+	AckTimeout time.Duration
+	// OnTrace, when not nil, is called with the decision trace of every plan.
+	OnTrace func(*hunting.Trace)
+	// OnResponse, when not nil, is called with every response before the bot acts on it.
+	OnResponse func(*deviant.EncounterResponse)
+	// OnComplete, when not nil, is called with the result of every encounter the bot sees completed.
+	OnComplete func(*Result)
+	// ExitOnComplete makes Run return once an encounter completes, rather than waiting for the next one.
+	ExitOnComplete bool
+	// Metrics, when not nil, counts what the bot does.
+	Metrics *metrics.Bot
+	// Record is a directory the bot records its encounter stream to, nothing is recorded when empty.
+	Record string
+	// Logger receives the bot's log entries, including its strategy's when it is strategy.Logged. Nothing is logged
+	// when it is nil.
+	Logger logging.Logger
+}
+
+// Bot a single player connected to an encounter server, playing its turns until stopped.
+type Bot struct {
+	config     Config
+	logger     logging.Logger
+	runtime    *Runtime
+	supervisor *client.Supervisor
+	recorder   *record.Recorder
+
+	mu      sync.Mutex
+	stopped bool
+	cancel  context.CancelFunc
+
+	// encounterID, alignment and reported follow the encounter being played, result holds the last one completed.
+	encounterID string
+	alignment   *deviant.Alignment
+	reported    bool
+	result      *Result
+	completed   bool
+}
+
+// New validates config and prepares a bot. It does not connect until Run is called.
+func New(config Config) (*Bot, error) {
+	if config.PlayerID == "" {
+		return nil, errors.New("a bot needs a player id")
+	}
+
+	if config.Open == nil {
+		return nil, errors.New("a bot needs a way to open streams to the server")
+	}
+
+	if config.Plan == nil && config.Strategy == nil {
+		selected, err := strategy.New(strategy.Default, strategy.DefaultDifficulty, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		config.Strategy = selected
+	}
+
+	logger := logging.OrNop(config.Logger).With(logging.Player(config.PlayerID))
+	if logged, ok := config.Strategy.(strategy.Logged); ok && config.Logger != nil {
+		config.Strategy = logged.WithLogger(logger)
+	}
+
+	b := &Bot{config: config, logger: logger}
+
+	b.supervisor = &client.Supervisor{
+		PlayerID: config.PlayerID,
+		Open:     config.Open,
+		Backoff:  config.Backoff,
+		Logger:   b.logger,
+	}
+
+	plan := config.Plan
+	if plan == nil {
+		plan = StrategyPlanner(config.Strategy, config.Hunt, config.OnTrace)
+	}
+
+	b.runtime = NewRuntime(config.PlayerID, plan, b.supervisor.Send)
+	b.runtime.Pacer = seeded(config.Pacer, config.Seed)
+	b.runtime.AckTimeout = config.AckTimeout
+	b.runtime.Logger = b.logger
+	b.supervisor.OnResponse = b.deliver
+	b.instrument()
+
+	if config.Record != "" {
+		recorder, err := record.New(config.Record, config.PlayerID)
+		if err != nil {
+			return nil, err
+		}
+
+		recorder.Logger = b.logger
+		b.recorder = recorder
+		// Recording what the supervisor sends rather than what the runtime sends keeps the registrations too.
+		b.supervisor.OnSend = recorder.Sent
+		b.supervisor.OnResponse = recorder.OnResponse(b.deliver)
+	}
+
+	return b, nil
+}
+
+// instrument reports what the runtime and supervisor do to the bot's metrics.
+func (b *Bot) instrument() {
+	botMetrics := b.config.Metrics
+	if botMetrics == nil {
+		return
+	}
+
+	b.runtime.OnPlan = func(plan *Plan, elapsed time.Duration) {
+		botMetrics.Planned(elapsed, plan.Complexity())
+	}
+
+	b.runtime.OnTurn = func(TurnKey) {
+		botMetrics.TurnPlayed()
+	}
+
+	b.runtime.OnReject = func(*deviant.EncounterRequest, error) {
+		botMetrics.Rejected()
+	}
+
+	b.supervisor.OnReconnect = func(error) {
+		botMetrics.Reconnected()
+	}
+}
+
+// deliver hands a response to the observer and then the runtime.
+func (b *Bot) deliver(response *deviant.EncounterResponse) {
+	if b.config.OnResponse != nil {
+		b.config.OnResponse(response)
+	}
+
+	if response.Encounter != nil {
+		b.observe(response.Encounter)
+	}
+
+	b.runtime.Deliver(response)
+}
+
+// observe remembers which side the bot plays in the current encounter and reports the encounter once it completes.
+func (b *Bot) observe(encounter *deviant.Encounter) {
+	b.mu.Lock()
+
+	if encounter.Id != b.encounterID {
+		b.encounterID = encounter.Id
+		b.alignment = nil
+		b.reported = false
+	}
+
+	// The bot's side has to be remembered, its entities may all be gone by the time the encounter completes.
+	if b.alignment == nil {
+		if alignment, ok := ownedAlignment(b.config.PlayerID, encounter); ok {
+			b.alignment = &alignment
+		}
+	}
+
+	if !encounter.Completed || b.reported {
+		b.mu.Unlock()
+		return
+	}
+
+	result := ResultOf(b.config.PlayerID, b.alignment, encounter)
+	b.reported = true
+	b.result = result
+	b.mu.Unlock()
+
+	b.logger.Info("Encounter completed",
+		logging.Encounter(result.EncounterID),
+		logging.Any("alignment", result.Alignment),
+		logging.Any("outcome", result.Outcome),
+		logging.Any("survivors", result.Survivors),
+		logging.Any("opponents", result.Opponents))
+	b.config.Metrics.Completed(result.Outcome.String())
+
+	if b.config.OnComplete != nil {
+		b.config.OnComplete(result)
+	}
+
+	if b.config.ExitOnComplete {
+		b.mu.Lock()
+		b.completed = true
+		b.cancel()
+		b.mu.Unlock()
+	}
+}
+
+// PlayerID returns the player the bot plays as.
+func (b *Bot) PlayerID() string {
+	return b.config.PlayerID
+}
+
+// Result returns the result of the last encounter the bot saw completed, nil when none has.
+func (b *Bot) Result() *Result {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.result
+}
+
+// Run connects to the server and plays until ctx is done or Stop is called, returning nil once stopped. With
+// ExitOnComplete it also returns nil once an encounter completes. A bot runs at most once.
+func (b *Bot) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	b.mu.Lock()
+	if b.stopped {
+		b.mu.Unlock()
+		return nil
+	}
+
+	if b.cancel != nil {
+		b.mu.Unlock()
+		return errors.New("the bot is already running")
+	}
+
+	b.cancel = cancel
+	b.mu.Unlock()
+
+	errs := make(chan error, 2)
+	go func() {
+		errs <- b.runtime.Run(ctx)
+	}()
+
+	go func() {
+		errs <- b.supervisor.Run(ctx)
+	}()
+
+	// Either half stopping for any reason stops the other.
+	err := <-errs
+	cancel()
+	<-errs
+
+	if b.recorder != nil {
+		if closeErr := b.recorder.Close(); closeErr != nil {
+			b.logger.Error("Failed to close the recording", logging.Err(closeErr))
+		}
+	}
+
+	b.mu.Lock()
+	finished := b.stopped || b.completed
+	b.mu.Unlock()
+
+	if finished {
+		return nil
+	}
+
+	return err
+}
+
+// Stopped reports whether Stop has been called.
+func (b *Bot) Stopped() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.stopped
+}
+
+// Stop makes Run return. It is safe to call at any time and more than once.
+func (b *Bot) Stop() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.stopped = true
+	if b.cancel != nil {
+		b.cancel()
+	}
+}
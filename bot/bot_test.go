@@ -0,0 +1,349 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/recluse-games/deviant-glados/client"
+	"github.com/recluse-games/deviant-glados/internal/fakeserver"
+	"github.com/recluse-games/deviant-glados/logging"
+	"github.com/recluse-games/deviant-glados/record"
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+	"google.golang.org/grpc"
+)
+
+// startRegistrar serves a registrar over an in-memory listener and returns it with a connection to it.
+func startRegistrar(t *testing.T) (*registrar, *grpc.ClientConn) {
+	server := &registrar{registrations: map[string]bool{}}
+
+	return server, serveBufconn(t, server)
+}
+
+// serveBufconn serves server over an in-memory listener and returns a connection to it.
+func serveBufconn(t *testing.T, server deviant.EncounterServiceServer) *grpc.ClientConn {
+	listener := fakeserver.Serve(server)
+	t.Cleanup(listener.Stop)
+
+	conn, err := listener.Dial(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		conn.Close()
+	})
+
+	return conn
+}
+
+// scripted sends the same responses to every registered player and then waits for it to hang up.
+type scripted struct {
+	deviant.UnimplementedEncounterServiceServer
+
+	responses []*deviant.EncounterResponse
+}
+
+func (s *scripted) UpdateEncounter(stream deviant.EncounterService_UpdateEncounterServer) error {
+	if _, err := stream.Recv(); err != nil {
+		return err
+	}
+
+	for _, response := range s.responses {
+		if err := stream.Send(response); err != nil {
+			return err
+		}
+	}
+
+	for {
+		if _, err := stream.Recv(); err != nil {
+			return err
+		}
+	}
+}
+
+// syncBuffer a buffer safe to log to from several goroutines.
+type syncBuffer struct {
+	mu     sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buffer.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buffer.String()
+}
+
+func TestNewValidatesConfig(t *testing.T) {
+	open := client.OpenFunc(func(ctx context.Context) (deviant.EncounterService_UpdateEncounterClient, error) {
+		return nil, errors.New("unreachable")
+	})
+
+	cases := map[string]Config{
+		"missing player id": {Open: open},
+		"missing open":      {PlayerID: "0001"},
+	}
+
+	for name, config := range cases {
+		if _, err := New(config); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	b, err := New(Config{PlayerID: "0001", Open: open})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if b.PlayerID() != "0001" {
+		t.Fatalf("expected player 0001, got %s", b.PlayerID())
+	}
+}
+
+func TestBotRunsUntilStopped(t *testing.T) {
+	server, conn := startRegistrar(t)
+
+	b, err := New(Config{PlayerID: "0001", Open: client.Shared(conn)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- b.Run(context.Background())
+	}()
+
+	waitFor(t, func() bool { return server.registered() == 1 })
+
+	if b.Stopped() {
+		t.Fatal("expected a running bot not to report being stopped")
+	}
+
+	b.Stop()
+	b.Stop()
+
+	if !b.Stopped() {
+		t.Fatal("expected the bot to report being stopped")
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("expected a stopped bot to return nil, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the bot did not stop")
+	}
+
+	if err := b.Run(context.Background()); err != nil {
+		t.Fatalf("expected running a stopped bot to return nil, got %v", err)
+	}
+}
+
+func TestBotStopsWithItsContext(t *testing.T) {
+	server, conn := startRegistrar(t)
+
+	b, err := New(Config{PlayerID: "0001", Open: client.Shared(conn)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- b.Run(ctx)
+	}()
+
+	waitFor(t, func() bool { return server.registered() == 1 })
+
+	if err := b.Run(ctx); err == nil {
+		t.Fatal("expected a running bot to refuse to run twice")
+	}
+
+	cancel()
+
+	if err := <-done; err != context.Canceled {
+		t.Fatalf("expected the bot to stop with its context, got %v", err)
+	}
+}
+
+func TestBotLogsToItsLogger(t *testing.T) {
+	output := &syncBuffer{}
+
+	b, err := New(Config{
+		PlayerID: "0001",
+		Open: func(ctx context.Context) (deviant.EncounterService_UpdateEncounterClient, error) {
+			return nil, errors.New("connection refused")
+		},
+		Backoff: client.Backoff{Initial: time.Millisecond, Max: time.Millisecond, Multiplier: 1},
+		Logger:  logging.New(output, logging.Text, logging.Debug),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- b.Run(context.Background())
+	}()
+
+	waitFor(t, func() bool {
+		return strings.Contains(output.String(), `WARN Encounter stream lost, reconnecting delay=1ms error="connection refused" player=0001`)
+	})
+
+	b.Stop()
+	<-done
+}
+
+func TestBotExitsOnceItsEncounterCompletes(t *testing.T) {
+	entity := &deviant.Entity{Id: "0001", OwnerId: "0001", Alignment: deviant.Alignment_UNFRIENDLY}
+	opponent := &deviant.Entity{Id: "0002", OwnerId: "0002", Alignment: deviant.Alignment_FRIENDLY}
+
+	playing := completedEncounter(entity, opponent)
+	playing.Completed = false
+	playing.ActiveEntity = opponent
+
+	// The bot's only entity is gone by the time the encounter completes, so it has to remember its side.
+	server := &scripted{responses: []*deviant.EncounterResponse{
+		{PlayerId: "0001", Encounter: playing},
+		{PlayerId: "0001", Encounter: completedEncounter(opponent)},
+		{PlayerId: "0001", Encounter: completedEncounter(opponent)},
+	}}
+
+	var mu sync.Mutex
+	responses := 0
+	results := []*Result{}
+
+	b, err := New(Config{
+		PlayerID: "0001",
+		Open:     client.Shared(serveBufconn(t, server)),
+		OnResponse: func(*deviant.EncounterResponse) {
+			mu.Lock()
+			responses++
+			mu.Unlock()
+		},
+		OnComplete: func(result *Result) {
+			mu.Lock()
+			results = append(results, result)
+			mu.Unlock()
+		},
+		ExitOnComplete: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- b.Run(context.Background())
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("expected the bot to stop cleanly, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		b.Stop()
+		t.Fatal("the bot did not stop once its encounter completed")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if responses < 2 {
+		t.Errorf("expected every response to be observed, got %d", responses)
+	}
+
+	if len(results) != 1 || results[0] != b.Result() {
+		t.Fatalf("expected the encounter to be reported once, got %v", results)
+	}
+
+	if result := b.Result(); result.Outcome != OutcomeLost || result.Alignment != deviant.Alignment_UNFRIENDLY {
+		t.Fatalf("expected the bot to have lost as UNFRIENDLY, got %v", result)
+	}
+}
+
+func TestBotRecordsItsRegistration(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	opponent := &deviant.Entity{Id: "0002", OwnerId: "0002", Alignment: deviant.Alignment_FRIENDLY}
+	server := &scripted{responses: []*deviant.EncounterResponse{
+		{PlayerId: "0001", Encounter: completedEncounter(opponent)},
+	}}
+
+	b, err := New(Config{
+		PlayerID:       "0001",
+		Open:           client.Shared(serveBufconn(t, server)),
+		Record:         dir,
+		ExitOnComplete: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := b.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := record.ReadFile(filepath.Join(dir, record.FileName("0001", "encounter_0000")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 2 || entries[0].Request.GetEncounterCreateAction() == nil || entries[1].Response == nil {
+		t.Fatalf("expected the registration to be recorded before the response, got %+v", entries)
+	}
+}
+
+func TestBotSeedsItsThinkTime(t *testing.T) {
+	open := client.OpenFunc(func(ctx context.Context) (deviant.EncounterService_UpdateEncounterClient, error) {
+		return nil, errors.New("unreachable")
+	})
+
+	delays := func(seed int64) []time.Duration {
+		b, err := New(Config{PlayerID: "0001", Open: open, Pacer: DefaultThinkTime, Seed: seed})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		result := []time.Duration{}
+		for i := 0; i < 5; i++ {
+			result = append(result, b.runtime.Pacer.Delay(40))
+		}
+
+		return result
+	}
+
+	if first, second := delays(7), delays(7); !reflect.DeepEqual(first, second) {
+		t.Errorf("expected the same seed to pause the same way, got %v and %v", first, second)
+	}
+
+	if first, second := delays(7), delays(8); reflect.DeepEqual(first, second) {
+		t.Errorf("expected different seeds to pause differently, got %v both times", first)
+	}
+
+	if DefaultThinkTime.Rand != nil {
+		t.Error("expected seeding a bot's think time to leave the one it was given alone")
+	}
+}
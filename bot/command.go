@@ -0,0 +1,260 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/recluse-games/deviant-glados/client"
+	"github.com/recluse-games/deviant-glados/hunting"
+	"github.com/recluse-games/deviant-glados/logging"
+	"github.com/recluse-games/deviant-glados/metrics"
+	"github.com/recluse-games/deviant-glados/strategy"
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+)
+
+// Exit codes returned by RunMain, so that scripts running bots can tell how they ended.
+const (
+	// ExitOK the bot won or drew its encounter, or the bots shut down cleanly when told to.
+	ExitOK = 0
+	// ExitFailed the bot could not start, stopped with an error, or could not tell how its encounter ended.
+	ExitFailed = 1
+	// ExitInterrupted a signal stopped the bot before its encounter completed.
+	ExitInterrupted = 3
+	// ExitLost the bot lost its encounter.
+	ExitLost = 4
+)
+
+// Options what the glados command was asked to do, one field per command line flag.
+type Options struct {
+	PlayerID   string
+	Trace      bool
+	AckTimeout time.Duration
+	Pace       string
+	Difficulty string
+	Seed       int64
+	Record     string
+	// Bots is the path of a JSON ManagerConfig. When set every bot in it is run instead of the single PlayerID bot.
+	Bots    string
+	Metrics string
+	Logging logging.Flags
+}
+
+// RunMain runs the bot or bots described by opts against server until they finish or a signal stops them, writing
+// results to stdout and logs and traces to stderr. It returns the exit code the process should end with.
+func RunMain(opts *Options, server *client.Config, stdout io.Writer, stderr io.Writer) int {
+	logger, err := opts.Logging.Logger(stderr)
+	if err != nil {
+		fmt.Fprintf(stderr, "Invalid logging flags: %v\n", err)
+		return ExitFailed
+	}
+
+	if opts.Bots != "" {
+		return runManager(opts, server, logger, stderr)
+	}
+
+	return runBot(opts, server, logger, stdout, stderr)
+}
+
+// onSignal calls stop on the first SIGINT or SIGTERM and exits at once on the second. The returned function stops
+// listening for signals.
+func onSignal(logger logging.Logger, stop func()) func() {
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	done := make(chan struct{})
+	go func() {
+		select {
+		case received := <-signals:
+			logger.Info("Shutting down", logging.Any("signal", received))
+			stop()
+		case <-done:
+			return
+		}
+
+		select {
+		case received := <-signals:
+			logger.Warn("Exiting without shutting down", logging.Any("signal", received))
+			os.Exit(ExitFailed)
+		case <-done:
+		}
+	}()
+
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
+
+// runBot plays a single encounter as the PlayerID player, printing its result once it completes.
+func runBot(opts *Options, server *client.Config, logger logging.Logger, stdout io.Writer, stderr io.Writer) int {
+	pacer, err := ParsePacer(opts.Pace)
+	if err != nil {
+		logger.Error("Invalid -pace", logging.Err(err))
+		return ExitFailed
+	}
+
+	selected, err := strategy.New(strategy.Default, opts.Difficulty, opts.Seed)
+	if err != nil {
+		logger.Error("Invalid -difficulty", logging.Err(err))
+		return ExitFailed
+	}
+
+	// Player 0001 owns the friendly entities.
+	hunt := deviant.Alignment_FRIENDLY
+	if opts.PlayerID == "0001" {
+		hunt = deviant.Alignment_UNFRIENDLY
+	}
+
+	botMetrics, stopMetrics, err := serveMetrics(logger, opts.Metrics)
+	if err != nil {
+		logger.Error("Failed to serve metrics", logging.Err(err))
+		return ExitFailed
+	}
+	defer stopMetrics()
+
+	b, err := New(Config{
+		PlayerID:       opts.PlayerID,
+		Open:           client.Redial(server),
+		Strategy:       selected,
+		Hunt:           &hunt,
+		Pacer:          pacer,
+		Seed:           opts.Seed,
+		AckTimeout:     opts.AckTimeout,
+		OnTrace:        traceFunc(logger, opts.Trace, stderr),
+		ExitOnComplete: true,
+		Metrics:        botMetrics.Bot(strategy.Default, opts.Difficulty),
+		Record:         opts.Record,
+		Logger:         logger.With(logging.Strategy(strategy.Default)),
+	})
+	if err != nil {
+		logger.Error("Invalid bot", logging.Err(err))
+		return ExitFailed
+	}
+
+	defer onSignal(logger, b.Stop)()
+
+	if err := b.Run(context.Background()); err != nil {
+		logger.Error("Bot stopped", logging.Err(err))
+		return ExitFailed
+	}
+
+	result := b.Result()
+	if result != nil {
+		fmt.Fprintln(stdout, result)
+	}
+
+	return exitCode(result, b.Stopped())
+}
+
+// exitCode tells how a single bot's run ended from the result of its encounter, nil when none completed, and
+// whether it was stopped. An encounter that completed before the bot was stopped still counts.
+func exitCode(result *Result, stopped bool) int {
+	if result == nil {
+		if stopped {
+			return ExitInterrupted
+		}
+
+		return ExitFailed
+	}
+
+	switch result.Outcome {
+	case OutcomeWon, OutcomeDrawn:
+		return ExitOK
+	case OutcomeLost:
+		return ExitLost
+	}
+
+	return ExitFailed
+}
+
+// serveMetrics serves Prometheus metrics at address, returning the collectors to instrument bots with and a function
+// that stops serving them. Without an address nothing is served and the collectors are nil.
+func serveMetrics(logger logging.Logger, address string) (*metrics.Metrics, func(), error) {
+	if address == "" {
+		return nil, func() {}, nil
+	}
+
+	collectors, err := metrics.New(prometheus.DefaultRegisterer)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	server, err := metrics.Serve(address, prometheus.DefaultGatherer)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	logger.Info("Serving metrics", logging.Any("address", server.Addr), logging.Any("path", metrics.Path))
+
+	return collectors, func() {
+		server.Close()
+	}, nil
+}
+
+// traceFunc writes decision traces to w when enabled.
+func traceFunc(logger logging.Logger, enabled bool, w io.Writer) func(*hunting.Trace) {
+	if !enabled {
+		return nil
+	}
+
+	return func(turnTrace *hunting.Trace) {
+		if err := turnTrace.WriteJSON(w); err != nil {
+			logger.Error("Failed to write trace", logging.Err(err))
+		}
+	}
+}
+
+// runManager runs every bot in the Bots configuration over pooled connections until a signal stops them.
+func runManager(opts *Options, server *client.Config, logger logging.Logger, stderr io.Writer) int {
+	managerConfig, err := LoadManagerConfig(opts.Bots)
+	if err != nil {
+		logger.Error("Failed to load bots", logging.Err(err))
+		return ExitFailed
+	}
+
+	if opts.Record != "" {
+		managerConfig.Record = opts.Record
+	}
+
+	managerMetrics, stopMetrics, err := serveMetrics(logger, opts.Metrics)
+	if err != nil {
+		logger.Error("Failed to serve metrics", logging.Err(err))
+		return ExitFailed
+	}
+	defer stopMetrics()
+
+	managerConfig.Metrics = managerMetrics
+	managerConfig.Logger = logger
+
+	pool := client.NewPool()
+	defer pool.Close()
+
+	manager, err := NewManager(managerConfig, server, pool)
+	if err != nil {
+		logger.Error("Invalid bots", logging.Err(err))
+		return ExitFailed
+	}
+
+	if opts.Trace {
+		manager.Trace = stderr
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	defer onSignal(logger, cancel)()
+
+	logger.Info("Running bots", logging.Any("bots", len(manager.Bots())))
+
+	if err := manager.Run(ctx); err != nil && err != context.Canceled {
+		logger.Error("Bots stopped", logging.Err(err))
+		return ExitFailed
+	}
+
+	return ExitOK
+}
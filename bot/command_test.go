@@ -0,0 +1,75 @@
+package bot
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/recluse-games/deviant-glados/client"
+	"github.com/recluse-games/deviant-glados/logging"
+)
+
+func TestRunMainFailsOnInvalidOptions(t *testing.T) {
+	valid := func() *Options {
+		return &Options{
+			PlayerID:   "0000",
+			Pace:       "none",
+			Difficulty: "normal",
+			Logging:    logging.Flags{Level: "info", Format: "text"},
+		}
+	}
+
+	tests := map[string]struct {
+		change  func(*Options)
+		message string
+	}{
+		"logging":    {func(o *Options) { o.Logging.Level = "loud" }, "Invalid logging flags"},
+		"pace":       {func(o *Options) { o.Pace = "sprint" }, "Invalid -pace"},
+		"difficulty": {func(o *Options) { o.Difficulty = "impossible" }, "Invalid -difficulty"},
+		"bots":       {func(o *Options) { o.Bots = filepath.Join("testdata", "missing.json") }, "Failed to load bots"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			opts := valid()
+			test.change(opts)
+
+			stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+			if code := RunMain(opts, &client.Config{Address: "localhost:0"}, stdout, stderr); code != ExitFailed {
+				t.Fatalf("expected exit code %d, got %d", ExitFailed, code)
+			}
+
+			if !strings.Contains(stderr.String(), test.message) {
+				t.Errorf("expected %q to be logged, got %q", test.message, stderr.String())
+			}
+
+			if stdout.Len() != 0 {
+				t.Errorf("expected no result, got %q", stdout.String())
+			}
+		})
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	tests := map[string]struct {
+		result  *Result
+		stopped bool
+		want    int
+	}{
+		"won":                  {&Result{Outcome: OutcomeWon}, false, ExitOK},
+		"drawn":                {&Result{Outcome: OutcomeDrawn}, false, ExitOK},
+		"lost":                 {&Result{Outcome: OutcomeLost}, false, ExitLost},
+		"unknown":              {&Result{Outcome: OutcomeUnknown}, false, ExitFailed},
+		"interrupted":          {nil, true, ExitInterrupted},
+		"won before the stop":  {&Result{Outcome: OutcomeWon}, true, ExitOK},
+		"lost before the stop": {&Result{Outcome: OutcomeLost}, true, ExitLost},
+		"ended without result": {nil, false, ExitFailed},
+	}
+
+	for name, test := range tests {
+		if got := exitCode(test.result, test.stopped); got != test.want {
+			t.Errorf("%s: expected exit code %d, got %d", name, test.want, got)
+		}
+	}
+}
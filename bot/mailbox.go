@@ -0,0 +1,74 @@
+package bot
+
+import (
+	"context"
+	"sync"
+
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+)
+
+// Mailbox holds only the most recent encounter snapshot. Older snapshots are overwritten rather than queued, so
+// a slow consumer always works from the current state of the board.
+type Mailbox struct {
+	mu      sync.Mutex
+	latest  *deviant.EncounterResponse
+	version uint64
+	changed chan struct{}
+}
+
+// NewMailbox creates an empty mailbox.
+func NewMailbox() *Mailbox {
+	return &Mailbox{
+		changed: make(chan struct{}),
+	}
+}
+
+// Put replaces the current snapshot and wakes anyone waiting for a newer one.
+func (m *Mailbox) Put(encounterResponse *deviant.EncounterResponse) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.latest = encounterResponse
+	m.version++
+
+	close(m.changed)
+	m.changed = make(chan struct{})
+}
+
+// Latest returns the current snapshot and its version. The version is zero before anything has been put.
+func (m *Mailbox) Latest() (*deviant.EncounterResponse, uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.latest, m.version
+}
+
+// Changed returns a channel that is closed once a snapshot newer than version is put.
+func (m *Mailbox) Changed(version uint64) <-chan struct{} {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.version > version {
+		closed := make(chan struct{})
+		close(closed)
+
+		return closed
+	}
+
+	return m.changed
+}
+
+// Wait blocks until a snapshot newer than version is available and returns it.
+func (m *Mailbox) Wait(ctx context.Context, version uint64) (*deviant.EncounterResponse, uint64, error) {
+	for {
+		select {
+		case <-m.Changed(version):
+			latest, latestVersion := m.Latest()
+			if latestVersion > version {
+				return latest, latestVersion, nil
+			}
+		case <-ctx.Done():
+			return nil, version, ctx.Err()
+		}
+	}
+}
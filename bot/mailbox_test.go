@@ -0,0 +1,73 @@
+package bot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+)
+
+func TestMailboxKeepsLatest(t *testing.T) {
+	mailbox := NewMailbox()
+
+	for _, id := range []string{"a", "b", "c"} {
+		mailbox.Put(&deviant.EncounterResponse{PlayerId: id})
+	}
+
+	latest, version, err := mailbox.Wait(context.Background(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if latest.PlayerId != "c" || version != 3 {
+		t.Fatalf("expected only the latest snapshot, got %v at %v", latest.PlayerId, version)
+	}
+}
+
+func TestMailboxWaitBlocksUntilNewer(t *testing.T) {
+	mailbox := NewMailbox()
+	mailbox.Put(&deviant.EncounterResponse{PlayerId: "a"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, _, err := mailbox.Wait(ctx, 1); err != context.DeadlineExceeded {
+		t.Fatalf("expected Wait to block without a newer snapshot, got %v", err)
+	}
+
+	go func() {
+		time.Sleep(time.Millisecond)
+		mailbox.Put(&deviant.EncounterResponse{PlayerId: "b"})
+	}()
+
+	latest, version, err := mailbox.Wait(context.Background(), 1)
+	if err != nil || latest.PlayerId != "b" || version != 2 {
+		t.Fatalf("expected the newer snapshot, got %v at %v: %v", latest, version, err)
+	}
+}
+
+func TestMailboxChanged(t *testing.T) {
+	mailbox := NewMailbox()
+	changed := mailbox.Changed(0)
+
+	select {
+	case <-changed:
+		t.Fatal("expected no change before a put")
+	default:
+	}
+
+	mailbox.Put(&deviant.EncounterResponse{})
+
+	select {
+	case <-changed:
+	default:
+		t.Fatal("expected a put to signal waiters")
+	}
+
+	select {
+	case <-mailbox.Changed(0):
+	default:
+		t.Fatal("expected an already newer mailbox to report a change immediately")
+	}
+}
@@ -0,0 +1,293 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/recluse-games/deviant-glados/client"
+	"github.com/recluse-games/deviant-glados/hunting"
+	"github.com/recluse-games/deviant-glados/logging"
+	"github.com/recluse-games/deviant-glados/metrics"
+	"github.com/recluse-games/deviant-glados/strategy"
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+)
+
+// BotConfig describes a single bot run by a Manager.
+type BotConfig struct {
+	ID string `json:"id"`
+	// Strategy names a registered strategy, strategy.Default when empty.
+	Strategy string `json:"strategy,omitempty"`
+	// Difficulty tunes how well the strategy plays, strategy.DefaultDifficulty when empty.
+	Difficulty string `json:"difficulty,omitempty"`
+	// Server overrides the manager's server address for this bot.
+	Server string `json:"server,omitempty"`
+	// Hunt fixes the alignment the bot hunts, otherwise it hunts whoever opposes the active entity.
+	Hunt string `json:"hunt,omitempty"`
+	// Pace is a pacing specification accepted by ParsePacer, the manager's pacing when empty.
+	Pace string `json:"pace,omitempty"`
+	// Seed seeds any randomness in the bot's play, which is recorded in its decision traces.
+	Seed int64 `json:"seed,omitempty"`
+}
+
+// ManagerConfig describes the bots run by a Manager.
+type ManagerConfig struct {
+	// Pace is the default pacing specification for every bot, no pacing when empty.
+	Pace string `json:"pace,omitempty"`
+	// AckTimeout is how long every bot waits for each action to be acknowledged, such as "5s".
+	AckTimeout string `json:"ackTimeout,omitempty"`
+	// Record is a directory every bot records its encounter stream to, one file per bot and encounter. Nothing is
+	// recorded when it is empty.
+	Record string      `json:"record,omitempty"`
+	Bots   []BotConfig `json:"bots"`
+	// Metrics, when not nil, counts what every bot does under its strategy and difficulty.
+	Metrics *metrics.Metrics `json:"-"`
+	// Logger receives the entries of the manager and every bot, tagged with the bot's strategy. Nothing is logged
+	// when it is nil.
+	Logger logging.Logger `json:"-"`
+}
+
+// ReadManagerConfig decodes a JSON manager configuration.
+func ReadManagerConfig(r io.Reader) (*ManagerConfig, error) {
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+
+	config := &ManagerConfig{}
+	if err := decoder.Decode(config); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// LoadManagerConfig reads a JSON manager configuration from a file.
+func LoadManagerConfig(path string) (*ManagerConfig, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	config, err := ReadManagerConfig(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+
+	return config, nil
+}
+
+// StrategyPlanner plans turns with a strategy. When hunt is nil the bot hunts whoever opposes the active entity.
+// onTrace, when not nil, is called with the decision trace of every plan. Strategies cannot be interrupted, so the
+// planner gives up waiting on one as soon as ctx is done and leaves it to finish in the background.
+func StrategyPlanner(s strategy.Strategy, hunt *deviant.Alignment, onTrace func(*hunting.Trace)) Planner {
+	return func(ctx context.Context, encounterResponse *deviant.EncounterResponse) (*Plan, error) {
+		var alignment deviant.Alignment
+		if hunt != nil {
+			alignment = *hunt
+		} else {
+			alignment = strategy.OpposingAlignment(encounterResponse.Encounter.ActiveEntity.Alignment)
+		}
+
+		result := make(chan planResult, 1)
+		go func() {
+			defer func() {
+				if recovered := recover(); recovered != nil {
+					result <- planResult{err: fmt.Errorf("strategy panicked: %v", recovered)}
+				}
+			}()
+
+			requests, turnTrace := s.TakeTurn(encounterResponse, alignment)
+			result <- planResult{plan: &Plan{Requests: requests, Trace: turnTrace}}
+		}()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case planned := <-result:
+			if planned.err != nil {
+				return nil, planned.err
+			}
+
+			if onTrace != nil {
+				onTrace(planned.plan.Trace)
+			}
+
+			return planned.plan, ctx.Err()
+		}
+	}
+}
+
+// managedBot a bot along with the configuration it was created from.
+type managedBot struct {
+	config BotConfig
+	bot    *Bot
+}
+
+// Manager runs many bots in one process, sharing a connection per server between them.
+type Manager struct {
+	// Trace receives the decision traces of every bot when it is not nil.
+	Trace io.Writer
+
+	logger logging.Logger
+
+	mu   sync.Mutex
+	bots []*managedBot
+}
+
+// writeTrace writes a bot's trace, keeping traces from concurrent bots apart.
+func (m *Manager) writeTrace(trace *hunting.Trace) {
+	if m.Trace == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := trace.WriteJSON(m.Trace); err != nil {
+		m.logger.Error("Failed to write trace", logging.Err(err))
+	}
+}
+
+// NewManager validates config and prepares its bots. Bots connect through pool using server, with the address
+// replaced for bots that name their own server.
+func NewManager(config *ManagerConfig, server *client.Config, pool *client.Pool) (*Manager, error) {
+	if len(config.Bots) == 0 {
+		return nil, fmt.Errorf("no bots configured")
+	}
+
+	var ackTimeout time.Duration
+	if config.AckTimeout != "" {
+		timeout, err := time.ParseDuration(config.AckTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ackTimeout: %v", err)
+		}
+
+		ackTimeout = timeout
+	}
+
+	manager := &Manager{logger: logging.OrNop(config.Logger)}
+	seen := map[string]bool{}
+
+	for _, botConfig := range config.Bots {
+		if botConfig.ID == "" {
+			return nil, fmt.Errorf("bot without an id")
+		}
+
+		if seen[botConfig.ID] {
+			return nil, fmt.Errorf("bot %s is configured more than once", botConfig.ID)
+		}
+
+		seen[botConfig.ID] = true
+
+		bot, err := manager.newBot(botConfig, config, ackTimeout, server, pool)
+		if err != nil {
+			return nil, fmt.Errorf("bot %s: %v", botConfig.ID, err)
+		}
+
+		manager.bots = append(manager.bots, bot)
+	}
+
+	return manager, nil
+}
+
+func (m *Manager) newBot(config BotConfig, managerConfig *ManagerConfig, ackTimeout time.Duration, server *client.Config, pool *client.Pool) (*managedBot, error) {
+	if config.Strategy == "" {
+		config.Strategy = strategy.Default
+	}
+
+	if config.Difficulty == "" {
+		config.Difficulty = strategy.DefaultDifficulty
+	}
+
+	s, err := strategy.New(config.Strategy, config.Difficulty, config.Seed)
+	if err != nil {
+		return nil, err
+	}
+
+	var hunt *deviant.Alignment
+	if config.Hunt != "" {
+		value, ok := deviant.Alignment_value[config.Hunt]
+		if !ok {
+			return nil, fmt.Errorf("unknown alignment %q", config.Hunt)
+		}
+
+		alignment := deviant.Alignment(value)
+		hunt = &alignment
+	}
+
+	pace := managerConfig.Pace
+	if config.Pace != "" {
+		pace = config.Pace
+	}
+
+	var pacer Pacer = NoDelay{}
+	if pace != "" {
+		if pacer, err = ParsePacer(pace); err != nil {
+			return nil, err
+		}
+	}
+
+	serverConfig := *server
+	if config.Server != "" {
+		serverConfig.Address = config.Server
+	}
+
+	var logger logging.Logger
+	if managerConfig.Logger != nil {
+		logger = managerConfig.Logger.With(logging.Strategy(config.Strategy))
+	}
+
+	bot, err := New(Config{
+		PlayerID:   config.ID,
+		Open:       pool.Open(&serverConfig),
+		Strategy:   s,
+		Hunt:       hunt,
+		Pacer:      pacer,
+		Seed:       config.Seed,
+		AckTimeout: ackTimeout,
+		OnTrace:    m.writeTrace,
+		Metrics:    managerConfig.Metrics.Bot(config.Strategy, config.Difficulty),
+		Record:     managerConfig.Record,
+		Logger:     logger,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &managedBot{config: config, bot: bot}, nil
+}
+
+// Bots returns the configuration of every bot, with defaults filled in.
+func (m *Manager) Bots() []BotConfig {
+	configs := []BotConfig{}
+	for _, bot := range m.bots {
+		configs = append(configs, bot.config)
+	}
+
+	return configs
+}
+
+// Run runs every bot until ctx is done. Each bot has its own supervised stream and runtime goroutines.
+func (m *Manager) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+
+	for _, bot := range m.bots {
+		wg.Add(1)
+
+		go func(bot *managedBot) {
+			defer wg.Done()
+
+			if err := bot.bot.Run(ctx); err != nil && ctx.Err() == nil {
+				m.logger.Error("Bot stopped", logging.Player(bot.config.ID), logging.Err(err))
+			}
+		}(bot)
+	}
+
+	wg.Wait()
+
+	return ctx.Err()
+}
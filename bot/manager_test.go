@@ -0,0 +1,198 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/recluse-games/deviant-glados/client"
+	"github.com/recluse-games/deviant-glados/hunting"
+	"github.com/recluse-games/deviant-glados/internal/fakeserver"
+	"github.com/recluse-games/deviant-glados/strategy"
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+)
+
+// registrar records every registration and keeps the stream open without ever giving anyone a turn.
+type registrar struct {
+	deviant.UnimplementedEncounterServiceServer
+
+	mu            sync.Mutex
+	registrations map[string]bool
+}
+
+func (r *registrar) UpdateEncounter(stream deviant.EncounterService_UpdateEncounterServer) error {
+	registration, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	r.registrations[registration.PlayerId] = true
+	r.mu.Unlock()
+
+	for {
+		if _, err := stream.Recv(); err != nil {
+			return err
+		}
+	}
+}
+
+func (r *registrar) registered() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.registrations)
+}
+
+func TestReadManagerConfig(t *testing.T) {
+	config, err := ReadManagerConfig(strings.NewReader(`{
+		"pace": "none",
+		"record": "recordings",
+		"bots": [
+			{"id": "0001", "strategy": "hunting", "difficulty": "normal"},
+			{"id": "0002", "server": "10.0.0.1:50051", "hunt": "FRIENDLY"}
+		]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(config.Bots) != 2 || config.Bots[1].Server != "10.0.0.1:50051" || config.Bots[1].Hunt != "FRIENDLY" || config.Record != "recordings" {
+		t.Fatalf("unexpected configuration %+v", config)
+	}
+
+	if _, err := ReadManagerConfig(strings.NewReader(`{"bots": [{"id": "0001", "level": 3}]}`)); err == nil {
+		t.Fatal("expected unknown fields to be rejected")
+	}
+}
+
+func TestNewManagerValidatesBots(t *testing.T) {
+	cases := map[string][]BotConfig{
+		"no bots":            {},
+		"missing id":         {{}},
+		"duplicate id":       {{ID: "0001"}, {ID: "0001"}},
+		"unknown strategy":   {{ID: "0001", Strategy: "telepathy"}},
+		"unknown difficulty": {{ID: "0001", Difficulty: "impossible"}},
+		"unknown alignment":  {{ID: "0001", Hunt: "EVERYONE"}},
+		"invalid pace":       {{ID: "0001", Pace: "slowly"}},
+	}
+
+	for name, bots := range cases {
+		if _, err := NewManager(&ManagerConfig{Bots: bots}, &client.Config{}, client.NewPool()); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	manager, err := NewManager(&ManagerConfig{Bots: []BotConfig{{ID: "0001"}}}, &client.Config{}, client.NewPool())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bots := manager.Bots(); bots[0].Strategy != strategy.Default || bots[0].Difficulty != strategy.DefaultDifficulty {
+		t.Fatalf("expected defaults to be filled in, got %+v", bots[0])
+	}
+}
+
+func TestManagerRunsBotsOverSharedConnection(t *testing.T) {
+	server := &registrar{registrations: map[string]bool{}}
+	listener := fakeserver.Serve(server)
+	defer listener.Stop()
+
+	pool := client.NewPool(listener.DialOption())
+	defer pool.Close()
+
+	config := &ManagerConfig{}
+	for _, id := range []string{"0001", "0002", "0003", "0004"} {
+		config.Bots = append(config.Bots, BotConfig{ID: id})
+	}
+
+	manager, err := NewManager(config, &client.Config{Address: "bufnet"}, pool)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- manager.Run(ctx)
+	}()
+
+	waitFor(t, func() bool { return server.registered() == 4 })
+	cancel()
+
+	if err := <-done; err != context.Canceled {
+		t.Fatalf("expected the manager to stop with its context, got %v", err)
+	}
+
+	if pool.Len() != 1 {
+		t.Fatalf("expected every bot to share one connection, got %d", pool.Len())
+	}
+}
+
+func TestStrategyPlannerHuntsOpposingAlignment(t *testing.T) {
+	hunted := []deviant.Alignment{}
+	s := strategy.Func(func(encounterResponse *deviant.EncounterResponse, alignmentToHunt deviant.Alignment) ([]*deviant.EncounterRequest, *hunting.Trace) {
+		hunted = append(hunted, alignmentToHunt)
+		return nil, nil
+	})
+
+	response := generateResponse("turn_0000", "0001", "0001", deviant.TurnPhaseNames_PHASE_ACTION)
+	response.Encounter.ActiveEntity.Alignment = deviant.Alignment_UNFRIENDLY
+
+	traced := 0
+	StrategyPlanner(s, nil, func(*hunting.Trace) { traced++ })(context.Background(), response)
+
+	neutral := deviant.Alignment_NEUTRAL
+	StrategyPlanner(s, &neutral, nil)(context.Background(), response)
+
+	if len(hunted) != 2 || hunted[0] != deviant.Alignment_FRIENDLY || hunted[1] != deviant.Alignment_NEUTRAL {
+		t.Fatalf("expected the opposing alignment unless one is fixed, got %v", hunted)
+	}
+
+	if traced != 1 {
+		t.Fatalf("expected the trace callback once, got %d", traced)
+	}
+}
+
+func TestStrategyPlannerStopsWaitingWhenCancelled(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	s := strategy.Func(func(encounterResponse *deviant.EncounterResponse, alignmentToHunt deviant.Alignment) ([]*deviant.EncounterRequest, *hunting.Trace) {
+		<-release
+		return nil, nil
+	})
+
+	response := generateResponse("turn_0000", "0001", "0001", deviant.TurnPhaseNames_PHASE_ACTION)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := StrategyPlanner(s, nil, nil)(ctx, response)
+		done <- err
+	}()
+
+	cancel()
+
+	select {
+	case err := <-done:
+		if err != context.Canceled {
+			t.Fatalf("expected the planner to stop with its context, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the planner to stop waiting on the strategy once cancelled")
+	}
+}
+
+func TestStrategyPlannerReportsPanics(t *testing.T) {
+	s := strategy.Func(func(encounterResponse *deviant.EncounterResponse, alignmentToHunt deviant.Alignment) ([]*deviant.EncounterRequest, *hunting.Trace) {
+		panic("bad board")
+	})
+
+	response := generateResponse("turn_0000", "0001", "0001", deviant.TurnPhaseNames_PHASE_ACTION)
+	if _, err := StrategyPlanner(s, nil, nil)(context.Background(), response); err == nil || !strings.Contains(err.Error(), "bad board") {
+		t.Fatalf("expected the panic to be returned as an error, got %v", err)
+	}
+}
@@ -0,0 +1,177 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/recluse-games/deviant-glados/client"
+	"github.com/recluse-games/deviant-glados/encgen"
+	"github.com/recluse-games/deviant-glados/internal/fakeserver"
+	"github.com/recluse-games/deviant-glados/metrics"
+	"github.com/recluse-games/deviant-glados/strategy"
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+)
+
+// player a bot wired up as main.go does, along with the error its Run returned.
+type player struct {
+	bot *Bot
+	err error
+}
+
+func newPlayer(t *testing.T, server *fakeserver.Server, playerID string, botMetrics *metrics.Bot) *player {
+	conn, err := server.Dial(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		conn.Close()
+	})
+
+	b, err := New(Config{
+		PlayerID:       playerID,
+		Open:           client.Shared(conn),
+		AckTimeout:     5 * time.Second,
+		ExitOnComplete: true,
+		Metrics:        botMetrics,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &player{bot: b}
+}
+
+func (p *player) run(ctx context.Context, wg *sync.WaitGroup) {
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		p.err = p.bot.Run(ctx)
+	}()
+}
+
+func TestBotsPlayAWholeMatch(t *testing.T) {
+	if testing.Short() {
+		t.Skip("plays a whole match")
+	}
+
+	config := encgen.DefaultConfig()
+	config.Mirror = true
+	config.Seed = 3
+
+	encounter, err := encgen.Generate(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server, err := fakeserver.New(encounter)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server.MaxTurns = 200
+	server.Start()
+	defer server.Stop()
+
+	registry := prometheus.NewRegistry()
+	collectors, err := metrics.New(registry)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	players := []*player{
+		newPlayer(t, server, encgen.FriendlyPlayerID, collectors.Bot(strategy.Default, strategy.DefaultDifficulty)),
+		newPlayer(t, server, encgen.UnfriendlyPlayerID, collectors.Bot(strategy.Default, strategy.DefaultDifficulty)),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	for _, p := range players {
+		p.run(ctx, &wg)
+	}
+
+	select {
+	case <-server.Done():
+	case <-time.After(time.Minute):
+		t.Fatalf("the match did not finish, %d turns were played", server.Turns())
+	}
+
+	// Every player hears that the match is over and stops by itself.
+	stopped := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		cancel()
+		t.Fatal("the bots did not stop once the match was over")
+	}
+
+	cancel()
+
+	winner, won := server.Winner()
+	for _, p := range players {
+		if p.err != nil {
+			t.Errorf("expected %s to stop cleanly, got %v", p.bot.PlayerID(), p.err)
+		}
+
+		result := p.bot.Result()
+		if result == nil {
+			t.Errorf("expected %s to report a result", p.bot.PlayerID())
+			continue
+		}
+
+		if won && (result.Outcome == OutcomeWon) != (result.Alignment == winner) {
+			t.Errorf("expected %v to win, got %v", winner, result)
+		}
+	}
+
+	if registrations := server.Registrations(); len(registrations) != 2 {
+		t.Errorf("expected each player to register once, got %v", registrations)
+	}
+
+	if !won {
+		t.Errorf("expected a winner within %d turns", server.MaxTurns)
+	}
+
+	expected := `
+		# HELP glados_matches_total Completed encounters by how they ended for the bot.
+		# TYPE glados_matches_total counter
+		glados_matches_total{difficulty="normal",outcome="lost",strategy="hunting"} 1
+		glados_matches_total{difficulty="normal",outcome="won",strategy="hunting"} 1
+	`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(expected), "glados_matches_total"); err != nil {
+		t.Error(err)
+	}
+
+	// Requests arrive one turn at a time, each turn ended exactly once by its owner.
+	ended := map[string]int{}
+	for i, event := range server.Events() {
+		if event.Err != nil {
+			t.Errorf("request %d of %s in %s was rejected: %v", i, event.PlayerID, event.TurnID, event.Err)
+		}
+
+		if event.Request.EntityActionName == deviant.EntityActionNames_CHANGE_PHASE {
+			ended[event.TurnID]++
+		}
+	}
+
+	if turns := server.Turns(); len(ended) != turns {
+		t.Errorf("expected %d turns to be ended, got %d", turns, len(ended))
+	}
+
+	for turn, count := range ended {
+		if count != 1 {
+			t.Errorf("expected %s to be ended once, got %d", turn, count)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package bot
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// Clock abstracts waiting so that pacing can be tested without sleeping.
+type Clock interface {
+	After(d time.Duration) <-chan time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// SystemClock waits in real time.
+var SystemClock Clock = systemClock{}
+
+// Pacer decides how long a bot pauses before each action. Complexity is the number of candidates considered for
+// the decision behind the action, or zero for follow up actions of a decision already made.
+type Pacer interface {
+	Delay(complexity int) time.Duration
+}
+
+// NoDelay acts immediately.
+type NoDelay struct{}
+
+// Delay always returns zero.
+func (NoDelay) Delay(complexity int) time.Duration {
+	return 0
+}
+
+// FixedDelay pauses for the same time before every action.
+type FixedDelay time.Duration
+
+// Delay returns the fixed delay.
+func (f FixedDelay) Delay(complexity int) time.Duration {
+	return time.Duration(f)
+}
+
+// ThinkTime pauses for a randomised time that grows with the complexity of the decision, so bots appear to think
+// harder about busy boards.
+type ThinkTime struct {
+	Min time.Duration
+	Max time.Duration
+	// PerCandidate is added for each candidate considered.
+	PerCandidate time.Duration
+	// Jitter is the fraction of the delay that is randomised, between 0 and 1.
+	Jitter float64
+	// Rand is the source of randomness. When nil the shared, unseeded source is used, though bots give a ThinkTime
+	// without one a source seeded from their Config.Seed.
+	Rand *rand.Rand
+}
+
+// DefaultThinkTime is a think time that suits human opponents.
+var DefaultThinkTime = ThinkTime{
+	Min:          400 * time.Millisecond,
+	Max:          4 * time.Second,
+	PerCandidate: 5 * time.Millisecond,
+	Jitter:       0.5,
+}
+
+func (t ThinkTime) random() float64 {
+	if t.Rand != nil {
+		return t.Rand.Float64()
+	}
+
+	return rand.Float64()
+}
+
+// seeded gives a ThinkTime without a source of randomness one seeded from seed, leaving other pacers as they are.
+func seeded(pacer Pacer, seed int64) Pacer {
+	thinkTime, ok := pacer.(ThinkTime)
+	if !ok || thinkTime.Rand != nil {
+		return pacer
+	}
+
+	thinkTime.Rand = rand.New(rand.NewSource(seed))
+
+	return thinkTime
+}
+
+// Delay returns the think time for a decision.
+func (t ThinkTime) Delay(complexity int) time.Duration {
+	delay := float64(t.Min + time.Duration(complexity)*t.PerCandidate)
+	delay = delay * (1 - t.Jitter + 2*t.Jitter*t.random())
+
+	if delay < float64(t.Min) {
+		delay = float64(t.Min)
+	}
+
+	if t.Max > 0 && delay > float64(t.Max) {
+		delay = float64(t.Max)
+	}
+
+	return time.Duration(delay)
+}
+
+// ParsePacer builds a pacer from a specification of the form "none", "fixed:<duration>", "think" or
+// "think:<min>:<max>".
+func ParsePacer(spec string) (Pacer, error) {
+	parts := strings.Split(spec, ":")
+
+	durations := []time.Duration{}
+	for _, part := range parts[1:] {
+		duration, err := time.ParseDuration(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pacing %q: %v", spec, err)
+		}
+
+		durations = append(durations, duration)
+	}
+
+	switch {
+	case parts[0] == "none" && len(durations) == 0:
+		return NoDelay{}, nil
+	case parts[0] == "fixed" && len(durations) == 1:
+		return FixedDelay(durations[0]), nil
+	case parts[0] == "think" && len(durations) == 0:
+		return DefaultThinkTime, nil
+	case parts[0] == "think" && len(durations) == 2:
+		thinkTime := DefaultThinkTime
+		thinkTime.Min = durations[0]
+		thinkTime.Max = durations[1]
+
+		return thinkTime, nil
+	}
+
+	return nil, fmt.Errorf("invalid pacing %q, expected none, fixed:<duration>, think or think:<min>:<max>", spec)
+}
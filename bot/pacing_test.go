@@ -0,0 +1,122 @@
+package bot
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock records every requested pause and fires immediately.
+type fakeClock struct {
+	mu     sync.Mutex
+	delays []time.Duration
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.delays = append(c.delays, d)
+	fired := make(chan time.Time, 1)
+	fired <- time.Time{}
+
+	return fired
+}
+
+func (c *fakeClock) waited() []time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]time.Duration{}, c.delays...)
+}
+
+func TestParsePacer(t *testing.T) {
+	cases := map[string]Pacer{
+		"none":        NoDelay{},
+		"fixed:250ms": FixedDelay(250 * time.Millisecond),
+		"think":       DefaultThinkTime,
+		"think:1s:2s": ThinkTime{Min: time.Second, Max: 2 * time.Second, PerCandidate: DefaultThinkTime.PerCandidate, Jitter: DefaultThinkTime.Jitter},
+	}
+
+	for spec, expected := range cases {
+		pacer, err := ParsePacer(spec)
+		if err != nil {
+			t.Errorf("%s: %v", spec, err)
+			continue
+		}
+
+		if pacer != expected {
+			t.Errorf("%s: expected %v, got %v", spec, expected, pacer)
+		}
+	}
+
+	for _, spec := range []string{"", "fixed", "fixed:soon", "think:1s", "sometimes"} {
+		if _, err := ParsePacer(spec); err == nil {
+			t.Errorf("%q: expected an error", spec)
+		}
+	}
+}
+
+func TestThinkTimeScalesWithComplexity(t *testing.T) {
+	thinkTime := ThinkTime{
+		Min:          100 * time.Millisecond,
+		Max:          time.Second,
+		PerCandidate: 10 * time.Millisecond,
+	}
+
+	if delay := thinkTime.Delay(0); delay != 100*time.Millisecond {
+		t.Errorf("expected the minimum for a trivial decision, got %v", delay)
+	}
+
+	if delay := thinkTime.Delay(20); delay != 300*time.Millisecond {
+		t.Errorf("expected the delay to grow with candidates, got %v", delay)
+	}
+
+	if delay := thinkTime.Delay(1000); delay != time.Second {
+		t.Errorf("expected the delay to be capped, got %v", delay)
+	}
+}
+
+func TestThinkTimeJitterStaysInBounds(t *testing.T) {
+	thinkTime := ThinkTime{
+		Min:          100 * time.Millisecond,
+		Max:          time.Second,
+		PerCandidate: 10 * time.Millisecond,
+		Jitter:       0.5,
+		Rand:         rand.New(rand.NewSource(1)),
+	}
+
+	seen := map[time.Duration]bool{}
+	for i := 0; i < 100; i++ {
+		delay := thinkTime.Delay(40)
+		if delay < 250*time.Millisecond || delay > 750*time.Millisecond {
+			t.Fatalf("expected a delay within the jitter, got %v", delay)
+		}
+
+		seen[delay] = true
+	}
+
+	if len(seen) < 2 {
+		t.Fatal("expected jitter to vary the delay")
+	}
+}
+
+func TestRuntimeWaitsOnInjectedClock(t *testing.T) {
+	clock := &fakeClock{}
+	sent := &recorder{}
+
+	runtime := NewRuntime("0001", markerPlanner, sent.send)
+	runtime.Pacer = FixedDelay(time.Hour)
+	runtime.Clock = clock
+
+	stop := startRuntime(runtime)
+	defer stop()
+
+	runtime.Deliver(generateSnapshot("turn_0000", "a"))
+	waitFor(t, func() bool { return len(sent.sent()) == 1 })
+
+	if delays := clock.waited(); len(delays) != 1 || delays[0] != time.Hour {
+		t.Fatalf("expected a single paced action on the injected clock, got %v", delays)
+	}
+}
@@ -0,0 +1,117 @@
+package bot
+
+import (
+	"fmt"
+
+	"github.com/recluse-games/deviant-glados/strategy"
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+)
+
+// Outcome how a completed encounter ended for a player.
+type Outcome int
+
+// Possible outcomes of an encounter. OutcomeUnknown means the player's side could not be told from the encounter.
+const (
+	OutcomeUnknown Outcome = iota
+	OutcomeWon
+	OutcomeLost
+	OutcomeDrawn
+)
+
+var outcomeNames = map[Outcome]string{
+	OutcomeUnknown: "unknown",
+	OutcomeWon:     "won",
+	OutcomeLost:    "lost",
+	OutcomeDrawn:   "drawn",
+}
+
+func (o Outcome) String() string {
+	if name, ok := outcomeNames[o]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("Outcome(%d)", int(o))
+}
+
+// Result the summary of a completed encounter from one player's point of view.
+type Result struct {
+	EncounterID string
+	PlayerID    string
+	// Alignment is the side the player's entities fought on.
+	Alignment deviant.Alignment
+	Outcome   Outcome
+	// Survivors and Opponents count the entities left standing on the player's side and the opposing side.
+	Survivors int
+	Opponents int
+}
+
+func (r *Result) String() string {
+	if r.Outcome == OutcomeUnknown {
+		return fmt.Sprintf("encounter %s completed without any entities of player %s", r.EncounterID, r.PlayerID)
+	}
+
+	return fmt.Sprintf("player %s %s encounter %s as %v with %d entities standing against %d", r.PlayerID, r.Outcome, r.EncounterID, r.Alignment, r.Survivors, r.Opponents)
+}
+
+// ownedAlignment returns the alignment of the first entity on the board owned by playerID.
+func ownedAlignment(playerID string, encounter *deviant.Encounter) (deviant.Alignment, bool) {
+	for _, row := range encounter.GetBoard().GetEntities().GetEntities() {
+		for _, entity := range row.Entities {
+			if entity.Id != "" && entity.OwnerId == playerID {
+				return entity.Alignment, true
+			}
+		}
+	}
+
+	return 0, false
+}
+
+// ResultOf summarises a completed encounter for playerID. alignment is the player's side when already known, which
+// it must be once all of the player's entities are gone. The server's winner decides the outcome when it is set,
+// otherwise the outcome is told from who is left standing.
+func ResultOf(playerID string, alignment *deviant.Alignment, encounter *deviant.Encounter) *Result {
+	result := &Result{EncounterID: encounter.Id, PlayerID: playerID}
+
+	if alignment != nil {
+		result.Alignment = *alignment
+	} else if owned, ok := ownedAlignment(playerID, encounter); ok {
+		result.Alignment = owned
+	} else {
+		return result
+	}
+
+	opposing := strategy.OpposingAlignment(result.Alignment)
+	for _, row := range encounter.GetBoard().GetEntities().GetEntities() {
+		for _, entity := range row.Entities {
+			switch {
+			case entity.Id == "":
+			case entity.Alignment == result.Alignment:
+				result.Survivors++
+			case entity.Alignment == opposing:
+				result.Opponents++
+			}
+		}
+	}
+
+	// FRIENDLY is the zero value, so a FRIENDLY winner cannot be told apart from none and is left to the board.
+	if winner := encounter.WinningAlignment; winner != deviant.Alignment_FRIENDLY {
+		if winner == result.Alignment {
+			result.Outcome = OutcomeWon
+		} else {
+			result.Outcome = OutcomeLost
+		}
+
+		return result
+	}
+
+	switch {
+	case result.Survivors > 0 && result.Opponents == 0:
+		result.Outcome = OutcomeWon
+	case result.Survivors == 0 && result.Opponents > 0:
+		result.Outcome = OutcomeLost
+	default:
+		result.Outcome = OutcomeDrawn
+	}
+
+	return result
+}
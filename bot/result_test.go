@@ -0,0 +1,71 @@
+package bot
+
+import (
+	"testing"
+
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+)
+
+// completedEncounter builds a completed encounter with a single row holding the given entities.
+func completedEncounter(entities ...*deviant.Entity) *deviant.Encounter {
+	row := &deviant.EntitiesRow{Entities: []*deviant.Entity{{}}}
+	row.Entities = append(row.Entities, entities...)
+
+	return &deviant.Encounter{
+		Id:        "encounter_0000",
+		Completed: true,
+		Board: &deviant.Board{
+			Entities: &deviant.Entities{Entities: []*deviant.EntitiesRow{row}},
+		},
+	}
+}
+
+func TestResultOf(t *testing.T) {
+	friendly := deviant.Alignment_FRIENDLY
+	unfriendly := deviant.Alignment_UNFRIENDLY
+
+	survivor := &deviant.Entity{Id: "0001", OwnerId: "0001", Alignment: deviant.Alignment_FRIENDLY}
+	opponent := &deviant.Entity{Id: "0002", OwnerId: "0002", Alignment: deviant.Alignment_UNFRIENDLY}
+	bystander := &deviant.Entity{Id: "0003", Alignment: deviant.Alignment_NEUTRAL}
+
+	// The server can declare a winner while both sides are still standing.
+	declared := completedEncounter(survivor, opponent)
+	declared.WinningAlignment = deviant.Alignment_UNFRIENDLY
+
+	cases := []struct {
+		name      string
+		playerID  string
+		alignment *deviant.Alignment
+		encounter *deviant.Encounter
+		want      Result
+	}{
+		{"won", "0001", nil, completedEncounter(survivor, bystander), Result{Alignment: friendly, Outcome: OutcomeWon, Survivors: 1}},
+		{"lost", "0002", &unfriendly, completedEncounter(survivor), Result{Alignment: unfriendly, Outcome: OutcomeLost, Opponents: 1}},
+		{"drawn", "0001", &friendly, completedEncounter(), Result{Alignment: friendly, Outcome: OutcomeDrawn}},
+		{"declared lost", "0001", nil, declared, Result{Alignment: friendly, Outcome: OutcomeLost, Survivors: 1, Opponents: 1}},
+		{"declared won", "0002", nil, declared, Result{Alignment: unfriendly, Outcome: OutcomeWon, Survivors: 1, Opponents: 1}},
+		{"out of turns", "0002", nil, completedEncounter(survivor, opponent), Result{Alignment: unfriendly, Outcome: OutcomeDrawn, Survivors: 1, Opponents: 1}},
+		{"unknown side", "0002", nil, completedEncounter(survivor), Result{Outcome: OutcomeUnknown}},
+		{"no board", "0001", nil, &deviant.Encounter{Id: "encounter_0000", Completed: true}, Result{Outcome: OutcomeUnknown}},
+	}
+
+	for _, c := range cases {
+		c.want.EncounterID = "encounter_0000"
+		c.want.PlayerID = c.playerID
+
+		if got := ResultOf(c.playerID, c.alignment, c.encounter); *got != c.want {
+			t.Errorf("%s: expected %+v, got %+v", c.name, c.want, *got)
+		}
+	}
+}
+
+func TestResultString(t *testing.T) {
+	result := &Result{EncounterID: "encounter_0000", PlayerID: "0001", Alignment: deviant.Alignment_FRIENDLY, Outcome: OutcomeWon, Survivors: 2}
+	if got, want := result.String(), "player 0001 won encounter encounter_0000 as FRIENDLY with 2 entities standing against 0"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	if got := Outcome(7).String(); got != "Outcome(7)" {
+		t.Errorf("expected unnamed outcomes to show their value, got %q", got)
+	}
+}
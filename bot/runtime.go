@@ -0,0 +1,357 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/recluse-games/deviant-glados/hunting"
+	"github.com/recluse-games/deviant-glados/logging"
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+	"google.golang.org/protobuf/proto"
+)
+
+// errTurnOver is returned when the turn being planned ends before anything was sent.
+var errTurnOver = errors.New("turn ended before it was played")
+
+// errAckTimeout is returned when no response follows a request in time.
+var errAckTimeout = errors.New("timed out waiting for the server to acknowledge a request")
+
+// DefaultMaxReplans the number of times a turn is replanned after rejected requests before it is ended.
+const DefaultMaxReplans = 3
+
+// Plan the requests making up a turn and the trace explaining them.
+type Plan struct {
+	Requests []*deviant.EncounterRequest
+	Trace    *hunting.Trace
+}
+
+// Complexity returns the number of candidates considered while planning.
+func (p *Plan) Complexity() int {
+	if p.Trace == nil {
+		return 0
+	}
+
+	return len(p.Trace.Candidates)
+}
+
+// Planner plans a turn for the active entity. Implementations should give up promptly once ctx is cancelled; the
+// runtime discards the result of a cancelled plan either way.
+type Planner func(ctx context.Context, encounterResponse *deviant.EncounterResponse) (*Plan, error)
+
+// SendFunc delivers a single request to the encounter server.
+type SendFunc func(request *deviant.EncounterRequest) error
+
+// Runtime plays a single player's turns from the latest encounter snapshot.
+type Runtime struct {
+	PlayerID string
+	Plan     Planner
+	Send     SendFunc
+	// Pacer decides the pause before each request is sent, NoDelay when nil.
+	Pacer Pacer
+	// Clock waits out the pauses, SystemClock when nil.
+	Clock Clock
+	// AckTimeout is how long to wait for the response to each request before the final one. Zero sends the whole
+	// turn blindly.
+	AckTimeout time.Duration
+	// MaxReplans limits how often a turn is replanned after the server rejects a request.
+	MaxReplans int
+	// OnPlan, when not nil, is called with every plan made and how long making it took.
+	OnPlan func(plan *Plan, elapsed time.Duration)
+	// OnTurn, when not nil, is called once a turn has been played to the end.
+	OnTurn func(key TurnKey)
+	// OnReject, when not nil, is called with every request the server did not apply.
+	OnReject func(request *deviant.EncounterRequest, err error)
+	// Logger receives the runtime's log entries, nothing is logged when it is nil.
+	Logger logging.Logger
+
+	mailbox *Mailbox
+	turns   *TurnTracker
+}
+
+// NewRuntime creates a runtime for playerID.
+func NewRuntime(playerID string, plan Planner, send SendFunc) *Runtime {
+	return &Runtime{
+		PlayerID:   playerID,
+		Plan:       plan,
+		Send:       send,
+		MaxReplans: DefaultMaxReplans,
+		mailbox:    NewMailbox(),
+		turns:      NewTurnTracker(playerID),
+	}
+}
+
+// log returns the logger for entries about the turn identified by key.
+func (r *Runtime) log(key TurnKey) logging.Logger {
+	return logging.OrNop(r.Logger).With(logging.Encounter(key.EncounterID), logging.Turn(key.TurnID), logging.Entity(key.ActiveEntityID))
+}
+
+// Deliver hands the runtime a new encounter snapshot. It never blocks.
+func (r *Runtime) Deliver(encounterResponse *deviant.EncounterResponse) {
+	r.mailbox.Put(encounterResponse)
+}
+
+// Run plays turns until ctx is done.
+func (r *Runtime) Run(ctx context.Context) error {
+	var version uint64
+
+	for {
+		encounterResponse, latestVersion, err := r.mailbox.Wait(ctx, version)
+		if err != nil {
+			return err
+		}
+
+		version = latestVersion
+
+		key, act := r.turns.Observe(encounterResponse)
+		if !act {
+			continue
+		}
+
+		err = r.playTurn(ctx, key, encounterResponse, version)
+		if err == errTurnOver {
+			continue
+		}
+
+		if err != nil {
+			r.turns.Abort(key)
+
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			r.log(key).Error("Failed to play turn", logging.Err(err))
+			continue
+		}
+
+		r.turns.Done(key)
+
+		if r.OnTurn != nil {
+			r.OnTurn(key)
+		}
+	}
+}
+
+// boardChanged reports whether a newer snapshot differs in a way that invalidates a plan.
+func boardChanged(planned *deviant.EncounterResponse, latest *deviant.EncounterResponse) bool {
+	return !proto.Equal(planned.Encounter.Board, latest.Encounter.Board) || !proto.Equal(planned.Encounter.ActiveEntity, latest.Encounter.ActiveEntity)
+}
+
+type planResult struct {
+	plan *Plan
+	err  error
+}
+
+// runPlanner asks the planner for a plan, turning a panic into an error so that one bad board does not take the bot
+// down with it.
+func (r *Runtime) runPlanner(ctx context.Context, encounterResponse *deviant.EncounterResponse) (result planResult) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			result = planResult{err: fmt.Errorf("planner panicked: %v", recovered)}
+		}
+	}()
+
+	plan, err := r.Plan(ctx, encounterResponse)
+
+	return planResult{plan, err}
+}
+
+// plan runs the planner under a context that is cancelled if the board changes before it finishes. It returns
+// the plan along with the snapshot it was made from and the newest version seen.
+func (r *Runtime) plan(ctx context.Context, key TurnKey, encounterResponse *deviant.EncounterResponse, version uint64) (*Plan, *deviant.EncounterResponse, uint64, error) {
+	for {
+		planCtx, cancel := context.WithCancel(ctx)
+		result := make(chan planResult, 1)
+		started := time.Now()
+
+		go func(encounterResponse *deviant.EncounterResponse) {
+			result <- r.runPlanner(planCtx, encounterResponse)
+		}(encounterResponse)
+
+		replan := false
+		for !replan {
+			select {
+			case planned := <-result:
+				cancel()
+
+				if planned.err == nil && r.OnPlan != nil {
+					r.OnPlan(planned.plan, time.Since(started))
+				}
+
+				return planned.plan, encounterResponse, version, planned.err
+			case <-r.mailbox.Changed(version):
+				latest, latestVersion := r.mailbox.Latest()
+				version = latestVersion
+
+				// Keep planning when the snapshot only differs in ways the plan does not depend on.
+				if !boardChanged(encounterResponse, latest) {
+					continue
+				}
+
+				cancel()
+
+				if !KeyOf(latest.Encounter).sameTurn(key) || latest.Encounter.Completed {
+					return nil, nil, version, errTurnOver
+				}
+
+				r.log(key).Info("Board changed while planning, replanning")
+				encounterResponse = latest
+				replan = true
+			case <-ctx.Done():
+				cancel()
+
+				return nil, nil, version, ctx.Err()
+			}
+		}
+	}
+}
+
+func (r *Runtime) playTurn(ctx context.Context, key TurnKey, encounterResponse *deviant.EncounterResponse, version uint64) error {
+	replans := 0
+
+	for {
+		plan, planned, plannedVersion, err := r.plan(ctx, key, encounterResponse, version)
+		if err != nil {
+			return err
+		}
+
+		// Nothing has been sent yet, so a board change while waiting to send the first request means replanning.
+		if err := r.wait(ctx, plan.Complexity()); err != nil {
+			return err
+		}
+
+		if latest, latestVersion := r.mailbox.Latest(); latestVersion > plannedVersion && boardChanged(planned, latest) {
+			encounterResponse, version = latest, latestVersion
+			if !KeyOf(latest.Encounter).sameTurn(key) || latest.Encounter.Completed {
+				return errTurnOver
+			}
+
+			r.log(key).Info("Board changed before the turn was sent, replanning")
+			continue
+		}
+
+		latest, latestVersion, err := r.sendTurn(ctx, key, planned, plan.Requests)
+		if err != errDiverged {
+			return err
+		}
+
+		if replans >= r.MaxReplans {
+			r.log(key).Warn("Giving up on the turn", logging.Any("replans", replans))
+
+			return r.send(key, hunting.GenerateEndTurnAction(latest.Encounter))
+		}
+
+		replans++
+		encounterResponse, version = latest, latestVersion
+	}
+}
+
+// errDiverged is returned by sendTurn when the board no longer matches the plan and the rest must be replanned.
+var errDiverged = errors.New("board diverged from the plan")
+
+// sendTurn sends the planned requests, waiting for each one but the last to be acknowledged. When a response does
+// not show the expected effect it stops and returns errDiverged along with the snapshot to replan from.
+func (r *Runtime) sendTurn(ctx context.Context, key TurnKey, planned *deviant.EncounterResponse, requests []*deviant.EncounterRequest) (*deviant.EncounterResponse, uint64, error) {
+	current := planned
+	_, version := r.mailbox.Latest()
+
+	for i, request := range requests {
+		if i > 0 {
+			if err := r.wait(ctx, 0); err != nil {
+				return nil, version, err
+			}
+		}
+
+		_, version = r.mailbox.Latest()
+
+		if err := r.send(key, request); err != nil {
+			return nil, version, err
+		}
+
+		if r.AckTimeout <= 0 || i == len(requests)-1 {
+			continue
+		}
+
+		latest, latestVersion, err := r.awaitAck(ctx, version)
+		if err == errAckTimeout {
+			r.log(key).Warn("No response to a request, replanning", logging.Any("request", request))
+
+			// A response may have arrived since the wait gave up, so replan from the newest snapshot.
+			latest, latestVersion := r.mailbox.Latest()
+			if !KeyOf(latest.Encounter).sameTurn(key) || latest.Encounter.Completed {
+				return nil, latestVersion, nil
+			}
+
+			return latest, latestVersion, errDiverged
+		}
+
+		if err != nil {
+			return nil, version, err
+		}
+
+		version = latestVersion
+
+		if !KeyOf(latest.Encounter).sameTurn(key) || latest.Encounter.Completed {
+			return nil, version, nil
+		}
+
+		if err := verifyAck(request, current.Encounter, latest.Encounter); err != nil {
+			r.log(key).Warn("Request rejected, replanning", logging.Any("request", request), logging.Err(err))
+
+			if r.OnReject != nil {
+				r.OnReject(request, err)
+			}
+
+			return latest, version, errDiverged
+		}
+
+		current = latest
+	}
+
+	return nil, version, nil
+}
+
+func (r *Runtime) send(key TurnKey, request *deviant.EncounterRequest) error {
+	request.PlayerId = r.PlayerID
+	r.log(key).Debug("Sending request", logging.Any("request", request))
+
+	return r.Send(request)
+}
+
+// awaitAck waits for the first snapshot newer than version.
+func (r *Runtime) awaitAck(ctx context.Context, version uint64) (*deviant.EncounterResponse, uint64, error) {
+	ackCtx, cancel := context.WithTimeout(ctx, r.AckTimeout)
+	defer cancel()
+
+	latest, latestVersion, err := r.mailbox.Wait(ackCtx, version)
+	if err != nil && ctx.Err() == nil {
+		return nil, version, errAckTimeout
+	}
+
+	return latest, latestVersion, err
+}
+
+// wait pauses before an action for as long as the pacer asks.
+func (r *Runtime) wait(ctx context.Context, complexity int) error {
+	pacer, clock := r.Pacer, r.Clock
+	if pacer == nil {
+		pacer = NoDelay{}
+	}
+
+	if clock == nil {
+		clock = SystemClock
+	}
+
+	delay := pacer.Delay(complexity)
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	select {
+	case <-clock.After(delay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
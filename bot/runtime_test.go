@@ -0,0 +1,234 @@
+package bot
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+)
+
+// generateSnapshot builds a response for player 0001's turn with a marker entity describing the board.
+func generateSnapshot(turnID string, marker string) *deviant.EncounterResponse {
+	response := generateResponse(turnID, "0001", "0001", deviant.TurnPhaseNames_PHASE_POINT)
+	response.Encounter.Board = &deviant.Board{
+		Entities: &deviant.Entities{
+			Entities: []*deviant.EntitiesRow{
+				{Entities: []*deviant.Entity{{Id: marker}}},
+			},
+		},
+	}
+
+	return response
+}
+
+func markerOf(encounterResponse *deviant.EncounterResponse) string {
+	return encounterResponse.Encounter.Board.Entities.Entities[0].Entities[0].Id
+}
+
+// recorder collects sent requests.
+type recorder struct {
+	mu       sync.Mutex
+	requests []*deviant.EncounterRequest
+}
+
+func (r *recorder) send(request *deviant.EncounterRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.requests = append(r.requests, request)
+
+	return nil
+}
+
+func (r *recorder) sent() []*deviant.EncounterRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]*deviant.EncounterRequest{}, r.requests...)
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out")
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// markerPlanner plans a single request tagged with the board marker it was planned from.
+func markerPlanner(ctx context.Context, encounterResponse *deviant.EncounterResponse) (*Plan, error) {
+	return &Plan{
+		Requests: []*deviant.EncounterRequest{
+			{EntityTargetAction: &deviant.EntityTargetAction{Id: markerOf(encounterResponse)}},
+		},
+	}, nil
+}
+
+func startRuntime(runtime *Runtime) func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		runtime.Run(ctx)
+		close(done)
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+func TestRuntimePlaysTurnOnce(t *testing.T) {
+	sent := &recorder{}
+	runtime := NewRuntime("0001", markerPlanner, sent.send)
+	stop := startRuntime(runtime)
+	defer stop()
+
+	runtime.Deliver(generateSnapshot("turn_0000", "a"))
+	waitFor(t, func() bool { return len(sent.sent()) == 1 })
+
+	// Echoes of the same turn must not be planned again.
+	runtime.Deliver(generateSnapshot("turn_0000", "b"))
+	runtime.Deliver(generateSnapshot("turn_0000", "c"))
+	time.Sleep(10 * time.Millisecond)
+
+	requests := sent.sent()
+	if len(requests) != 1 || requests[0].PlayerId != "0001" || requests[0].EntityTargetAction.Id != "a" {
+		t.Fatalf("expected a single request for the first snapshot, got %v", requests)
+	}
+}
+
+func TestRuntimeReplansWhenBoardChangesDuringPlanning(t *testing.T) {
+	sent := &recorder{}
+	release := make(chan struct{})
+	cancelled := make(chan string, 2)
+
+	planner := func(ctx context.Context, encounterResponse *deviant.EncounterResponse) (*Plan, error) {
+		if markerOf(encounterResponse) == "stale" {
+			select {
+			case <-ctx.Done():
+				cancelled <- markerOf(encounterResponse)
+				return nil, ctx.Err()
+			case <-release:
+			}
+		}
+
+		return markerPlanner(ctx, encounterResponse)
+	}
+
+	runtime := NewRuntime("0001", planner, sent.send)
+	stop := startRuntime(runtime)
+	defer stop()
+
+	runtime.Deliver(generateSnapshot("turn_0000", "stale"))
+	time.Sleep(10 * time.Millisecond)
+	runtime.Deliver(generateSnapshot("turn_0000", "fresh"))
+
+	waitFor(t, func() bool { return len(sent.sent()) == 1 })
+	close(release)
+
+	select {
+	case marker := <-cancelled:
+		if marker != "stale" {
+			t.Errorf("expected the stale plan to be cancelled, got %v", marker)
+		}
+	case <-time.After(time.Second):
+		t.Error("expected the stale plan to be cancelled")
+	}
+
+	if requests := sent.sent(); requests[0].EntityTargetAction.Id != "fresh" {
+		t.Fatalf("expected the plan for the fresh board to be sent, got %v", requests)
+	}
+}
+
+func TestRuntimeReplansWhenBoardChangesBeforeSending(t *testing.T) {
+	sent := &recorder{}
+	planned := make(chan string, 4)
+
+	planner := func(ctx context.Context, encounterResponse *deviant.EncounterResponse) (*Plan, error) {
+		planned <- markerOf(encounterResponse)
+		return markerPlanner(ctx, encounterResponse)
+	}
+
+	runtime := NewRuntime("0001", planner, sent.send)
+	runtime.Pacer = FixedDelay(50 * time.Millisecond)
+	stop := startRuntime(runtime)
+	defer stop()
+
+	runtime.Deliver(generateSnapshot("turn_0000", "stale"))
+	if marker := <-planned; marker != "stale" {
+		t.Fatalf("expected the first snapshot to be planned, got %v", marker)
+	}
+
+	runtime.Deliver(generateSnapshot("turn_0000", "fresh"))
+	waitFor(t, func() bool { return len(sent.sent()) == 1 })
+
+	if requests := sent.sent(); requests[0].EntityTargetAction.Id != "fresh" {
+		t.Fatalf("expected the replanned turn to be sent, got %v", requests)
+	}
+}
+
+func TestRuntimeDropsTurnThatEndsWhilePlanning(t *testing.T) {
+	sent := &recorder{}
+	release := make(chan struct{})
+
+	planner := func(ctx context.Context, encounterResponse *deviant.EncounterResponse) (*Plan, error) {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-release:
+		}
+
+		return markerPlanner(ctx, encounterResponse)
+	}
+
+	runtime := NewRuntime("0001", planner, sent.send)
+	stop := startRuntime(runtime)
+
+	runtime.Deliver(generateSnapshot("turn_0000", "a"))
+	time.Sleep(10 * time.Millisecond)
+
+	other := generateResponse("turn_0001", "0002", "0002", deviant.TurnPhaseNames_PHASE_POINT)
+	other.Encounter.Board = generateSnapshot("turn_0001", "b").Encounter.Board
+	runtime.Deliver(other)
+	time.Sleep(10 * time.Millisecond)
+
+	close(release)
+	stop()
+
+	if requests := sent.sent(); len(requests) != 0 {
+		t.Fatalf("expected nothing to be sent for a turn that already ended, got %v", requests)
+	}
+}
+
+func TestRuntimeSurvivesPanickingPlanner(t *testing.T) {
+	sent := &recorder{}
+
+	planner := func(ctx context.Context, encounterResponse *deviant.EncounterResponse) (*Plan, error) {
+		if markerOf(encounterResponse) == "a" {
+			panic("index out of range")
+		}
+
+		return markerPlanner(ctx, encounterResponse)
+	}
+
+	runtime := NewRuntime("0001", planner, sent.send)
+	stop := startRuntime(runtime)
+	defer stop()
+
+	runtime.Deliver(generateSnapshot("turn_0000", "a"))
+	time.Sleep(10 * time.Millisecond)
+
+	runtime.Deliver(generateSnapshot("turn_0001", "b"))
+	waitFor(t, func() bool { return len(sent.sent()) == 1 })
+
+	if requests := sent.sent(); requests[0].EntityTargetAction.Id != "b" {
+		t.Fatalf("expected the next turn to be played after the planner panicked, got %v", requests)
+	}
+}
@@ -0,0 +1,140 @@
+package bot
+
+import (
+	"sync"
+
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+)
+
+// TurnKey identifies the state of an encounter that a bot may act on.
+type TurnKey struct {
+	EncounterID    string
+	TurnID         string
+	ActiveEntityID string
+	Phase          deviant.TurnPhaseNames
+}
+
+// KeyOf builds the turn key for an encounter.
+func KeyOf(encounter *deviant.Encounter) TurnKey {
+	key := TurnKey{
+		EncounterID: encounter.Id,
+	}
+
+	if encounter.Turn != nil {
+		key.TurnID = encounter.Turn.Id
+		key.Phase = encounter.Turn.Phase
+	}
+
+	if encounter.ActiveEntity != nil {
+		key.ActiveEntityID = encounter.ActiveEntity.Id
+	}
+
+	return key
+}
+
+// sameTurn reports whether two keys belong to the same turn of the same entity, regardless of phase.
+func (k TurnKey) sameTurn(other TurnKey) bool {
+	return k.EncounterID == other.EncounterID && k.TurnID == other.TurnID && k.ActiveEntityID == other.ActiveEntityID
+}
+
+// TurnState where a bot is in the lifecycle of a turn.
+type TurnState int
+
+// Turn states.
+const (
+	// TurnWaiting another player's entity is active.
+	TurnWaiting TurnState = iota
+	// TurnActing the bot has started planning or sending its turn.
+	TurnActing
+	// TurnDone every request for the turn has been sent.
+	TurnDone
+)
+
+func (s TurnState) String() string {
+	switch s {
+	case TurnWaiting:
+		return "waiting"
+	case TurnActing:
+		return "acting"
+	case TurnDone:
+		return "done"
+	}
+
+	return "unknown"
+}
+
+// TurnTracker decides when a bot should act so that each turn is played exactly once.
+//
+// Responses caused by the bot's own requests repeat the turn it is already playing. Those echoes, including ones
+// where the phase has moved on because of the bot's actions, are ignored until another entity becomes active.
+type TurnTracker struct {
+	PlayerID string
+
+	mu      sync.Mutex
+	state   TurnState
+	current TurnKey
+}
+
+// NewTurnTracker creates a tracker for the entities owned by playerID.
+func NewTurnTracker(playerID string) *TurnTracker {
+	return &TurnTracker{
+		PlayerID: playerID,
+	}
+}
+
+// Observe records an encounter response and reports whether the bot should start its turn. When it returns true
+// the caller owns the turn and must call Done or Abort with the returned key.
+func (t *TurnTracker) Observe(encounterResponse *deviant.EncounterResponse) (TurnKey, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	encounter := encounterResponse.Encounter
+	if encounter == nil || encounter.ActiveEntity == nil || encounter.ActiveEntity.OwnerId != t.PlayerID || encounter.Completed {
+		t.state = TurnWaiting
+		t.current = TurnKey{}
+
+		return TurnKey{}, false
+	}
+
+	key := KeyOf(encounter)
+
+	if t.state != TurnWaiting && key.sameTurn(t.current) {
+		t.current = key
+
+		return key, false
+	}
+
+	t.state = TurnActing
+	t.current = key
+
+	return key, true
+}
+
+// Done marks the turn as fully sent.
+func (t *TurnTracker) Done(key TurnKey) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.state == TurnActing && key.sameTurn(t.current) {
+		t.state = TurnDone
+	}
+}
+
+// Abort gives up on a turn that could not be sent so that the next response for it is acted on again.
+func (t *TurnTracker) Abort(key TurnKey) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if key.sameTurn(t.current) {
+		t.state = TurnWaiting
+		t.current = TurnKey{}
+	}
+}
+
+// State returns the current state and the key of the turn it applies to.
+func (t *TurnTracker) State() (TurnState, TurnKey) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.state, t.current
+}
@@ -0,0 +1,94 @@
+package bot
+
+import (
+	"testing"
+
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+)
+
+func generateResponse(turnID string, entityID string, ownerID string, phase deviant.TurnPhaseNames) *deviant.EncounterResponse {
+	return &deviant.EncounterResponse{
+		Encounter: &deviant.Encounter{
+			Id: "encounter_0000",
+			Turn: &deviant.Turn{
+				Id:    turnID,
+				Phase: phase,
+			},
+			ActiveEntity: &deviant.Entity{
+				Id:      entityID,
+				OwnerId: ownerID,
+			},
+		},
+	}
+}
+
+func TestTurnTrackerActsOncePerTurn(t *testing.T) {
+	tracker := NewTurnTracker("0001")
+
+	key, act := tracker.Observe(generateResponse("turn_0000", "0001", "0001", deviant.TurnPhaseNames_PHASE_POINT))
+	if !act {
+		t.Fatal("expected the first response of our turn to start it")
+	}
+
+	// Echoes of our own move, target and play requests.
+	for _, phase := range []deviant.TurnPhaseNames{deviant.TurnPhaseNames_PHASE_POINT, deviant.TurnPhaseNames_PHASE_POINT, deviant.TurnPhaseNames_PHASE_ACTION} {
+		if _, act := tracker.Observe(generateResponse("turn_0000", "0001", "0001", phase)); act {
+			t.Fatalf("expected an echo in %v to be ignored", phase)
+		}
+	}
+
+	tracker.Done(key)
+	if state, _ := tracker.State(); state != TurnDone {
+		t.Fatalf("expected the turn to be done, got %v", state)
+	}
+
+	if _, act := tracker.Observe(generateResponse("turn_0000", "0001", "0001", deviant.TurnPhaseNames_PHASE_END)); act {
+		t.Fatal("expected the end of our own turn to be ignored")
+	}
+}
+
+func TestTurnTrackerActsOnNewTurns(t *testing.T) {
+	tracker := NewTurnTracker("0001")
+
+	key, _ := tracker.Observe(generateResponse("turn_0000", "0001", "0001", deviant.TurnPhaseNames_PHASE_POINT))
+	tracker.Done(key)
+
+	if _, act := tracker.Observe(generateResponse("turn_0001", "0003", "0001", deviant.TurnPhaseNames_PHASE_POINT)); !act {
+		t.Fatal("expected a new turn for another of our entities to be acted on")
+	}
+
+	if _, act := tracker.Observe(generateResponse("turn_0002", "0002", "0002", deviant.TurnPhaseNames_PHASE_POINT)); act {
+		t.Fatal("expected another player's turn to be ignored")
+	}
+
+	if state, _ := tracker.State(); state != TurnWaiting {
+		t.Fatalf("expected to be waiting during another player's turn, got %v", state)
+	}
+
+	// Servers that reuse turn ids still hand the turn back through another entity first.
+	if _, act := tracker.Observe(generateResponse("turn_0000", "0001", "0001", deviant.TurnPhaseNames_PHASE_POINT)); !act {
+		t.Fatal("expected our entity's next turn to be acted on")
+	}
+}
+
+func TestTurnTrackerAbort(t *testing.T) {
+	tracker := NewTurnTracker("0001")
+
+	key, _ := tracker.Observe(generateResponse("turn_0000", "0001", "0001", deviant.TurnPhaseNames_PHASE_POINT))
+	tracker.Abort(key)
+
+	if _, act := tracker.Observe(generateResponse("turn_0000", "0001", "0001", deviant.TurnPhaseNames_PHASE_POINT)); !act {
+		t.Fatal("expected an aborted turn to be retried")
+	}
+}
+
+func TestTurnTrackerIgnoresCompletedEncounters(t *testing.T) {
+	tracker := NewTurnTracker("0001")
+
+	response := generateResponse("turn_0000", "0001", "0001", deviant.TurnPhaseNames_PHASE_POINT)
+	response.Encounter.Completed = true
+
+	if _, act := tracker.Observe(response); act {
+		t.Fatal("expected a completed encounter not to be acted on")
+	}
+}
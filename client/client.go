@@ -0,0 +1,209 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// DefaultAddress the encounter server used when no address is configured.
+const DefaultAddress = "127.0.0.1:50051"
+
+// Environment variables read by ConfigFromEnv.
+const (
+	EnvAddress       = "GLADOS_ADDRESS"
+	EnvTLS           = "GLADOS_TLS"
+	EnvCAFile        = "GLADOS_TLS_CA_FILE"
+	EnvCertFile      = "GLADOS_TLS_CERT_FILE"
+	EnvKeyFile       = "GLADOS_TLS_KEY_FILE"
+	EnvServerName    = "GLADOS_TLS_SERVER_NAME"
+	EnvToken         = "GLADOS_TOKEN"
+	EnvInsecureToken = "GLADOS_INSECURE_TOKEN"
+)
+
+// Config describes how to reach and authenticate with an encounter server.
+type Config struct {
+	Address string
+
+	// TLS enables transport security. It is implied when any certificate file or a server name override is set.
+	TLS                bool
+	CAFile             string
+	CertFile           string
+	KeyFile            string
+	ServerNameOverride string
+
+	// Token is sent as a bearer token in the authorization metadata of every RPC. It is only sent over TLS unless
+	// InsecureToken is set.
+	Token         string
+	InsecureToken bool
+
+	// PerRPCCredentials are attached to every RPC in addition to Token.
+	PerRPCCredentials credentials.PerRPCCredentials
+}
+
+// ConfigFromEnv builds a configuration from the environment, falling back to an insecure connection to
+// DefaultAddress. It fails when a boolean variable is not a boolean.
+func ConfigFromEnv() (*Config, error) {
+	config := &Config{
+		Address: DefaultAddress,
+	}
+
+	if address := os.Getenv(EnvAddress); address != "" {
+		config.Address = address
+	}
+
+	var err error
+	if config.TLS, err = envBool(EnvTLS); err != nil {
+		return nil, err
+	}
+
+	if config.InsecureToken, err = envBool(EnvInsecureToken); err != nil {
+		return nil, err
+	}
+
+	config.CAFile = os.Getenv(EnvCAFile)
+	config.CertFile = os.Getenv(EnvCertFile)
+	config.KeyFile = os.Getenv(EnvKeyFile)
+	config.ServerNameOverride = os.Getenv(EnvServerName)
+	config.Token = os.Getenv(EnvToken)
+
+	return config, nil
+}
+
+// envBool reads a boolean environment variable, which is false when unset.
+func envBool(key string) (bool, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return false, nil
+	}
+
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%s must be true or false, got %q", key, value)
+	}
+
+	return enabled, nil
+}
+
+// RegisterFlags binds the configuration to command line flags, using the current values as defaults.
+func (c *Config) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.Address, "addr", c.Address, "encounter server address (env "+EnvAddress+")")
+	fs.BoolVar(&c.TLS, "tls", c.TLS, "connect using TLS (env "+EnvTLS+")")
+	fs.StringVar(&c.CAFile, "tls-ca", c.CAFile, "PEM file of CAs trusted to sign the server certificate (env "+EnvCAFile+")")
+	fs.StringVar(&c.CertFile, "tls-cert", c.CertFile, "PEM client certificate for mutual TLS (env "+EnvCertFile+")")
+	fs.StringVar(&c.KeyFile, "tls-key", c.KeyFile, "PEM client key for mutual TLS (env "+EnvKeyFile+")")
+	fs.StringVar(&c.ServerNameOverride, "tls-server-name", c.ServerNameOverride, "override the server name used to verify the certificate (env "+EnvServerName+")")
+	fs.StringVar(&c.Token, "token", c.Token, "bearer token sent with every request (env "+EnvToken+")")
+	fs.BoolVar(&c.InsecureToken, "insecure-token", c.InsecureToken, "allow the token to be sent without TLS, in plaintext (env "+EnvInsecureToken+")")
+}
+
+func (c *Config) secure() bool {
+	return c.TLS || c.CAFile != "" || c.CertFile != "" || c.KeyFile != "" || c.ServerNameOverride != ""
+}
+
+// TLSConfig builds the client TLS configuration, or returns nil when TLS is disabled.
+func (c *Config) TLSConfig() (*tls.Config, error) {
+	if !c.secure() {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		ServerName: c.ServerNameOverride,
+	}
+
+	if c.CAFile != "" {
+		pem, err := ioutil.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, err
+		}
+
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", c.CAFile)
+		}
+	}
+
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return nil, errors.New("a client certificate and key must be provided together")
+	}
+
+	if c.CertFile != "" {
+		certificate, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	return tlsConfig, nil
+}
+
+// DialOptions converts the configuration into gRPC dial options.
+func (c *Config) DialOptions() ([]grpc.DialOption, error) {
+	options := []grpc.DialOption{}
+
+	tlsConfig, err := c.TLSConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if tlsConfig != nil {
+		options = append(options, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	} else {
+		options = append(options, grpc.WithInsecure())
+	}
+
+	if c.Token != "" {
+		if tlsConfig == nil && !c.InsecureToken {
+			return nil, errors.New("refusing to send a token without TLS, enable TLS or allow it with -insecure-token")
+		}
+
+		options = append(options, grpc.WithPerRPCCredentials(&bearerToken{
+			token:    c.Token,
+			insecure: c.InsecureToken,
+		}))
+	}
+
+	if c.PerRPCCredentials != nil {
+		options = append(options, grpc.WithPerRPCCredentials(c.PerRPCCredentials))
+	}
+
+	return options, nil
+}
+
+// Dial connects to the configured encounter server.
+func Dial(ctx context.Context, config *Config, extra ...grpc.DialOption) (*grpc.ClientConn, error) {
+	options, err := config.DialOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	return grpc.DialContext(ctx, config.Address, append(options, extra...)...)
+}
+
+// bearerToken attaches a static bearer token to each RPC.
+type bearerToken struct {
+	token    string
+	insecure bool
+}
+
+func (b *bearerToken) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{
+		"authorization": "Bearer " + b.token,
+	}, nil
+}
+
+// RequireTransportSecurity insists on TLS unless sending the token in plaintext was explicitly allowed.
+func (b *bearerToken) RequireTransportSecurity() bool {
+	return !b.insecure
+}
@@ -0,0 +1,337 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"flag"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const testServerName = "glados.test"
+
+type testCertificate struct {
+	certificate *x509.Certificate
+	key         *ecdsa.PrivateKey
+	der         []byte
+}
+
+func (c *testCertificate) tlsCertificate() tls.Certificate {
+	return tls.Certificate{
+		Certificate: [][]byte{c.der},
+		PrivateKey:  c.key,
+	}
+}
+
+func (c *testCertificate) writeFiles(t *testing.T, dir string, name string) (string, string) {
+	certFile := filepath.Join(dir, name+".crt")
+	keyFile := filepath.Join(dir, name+".key")
+
+	keyDER, err := x509.MarshalECPrivateKey(c.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	return certFile, keyFile
+}
+
+func generateCertificate(t *testing.T, template *x509.Certificate, parent *testCertificate) *testCertificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(time.Hour)
+
+	parentCertificate, parentKey := template, key
+	if parent != nil {
+		parentCertificate, parentKey = parent.certificate, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parentCertificate, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &testCertificate{
+		certificate: certificate,
+		key:         key,
+		der:         der,
+	}
+}
+
+// testPKI a throwaway CA with a server and client certificate issued by it.
+type testPKI struct {
+	ca     *testCertificate
+	server *testCertificate
+	client *testCertificate
+}
+
+func generatePKI(t *testing.T) *testPKI {
+	ca := generateCertificate(t, &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "glados test ca"},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, nil)
+
+	server := generateCertificate(t, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: testServerName},
+		DNSNames:     []string{testServerName},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, ca)
+
+	client := generateCertificate(t, &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "glados"},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, ca)
+
+	return &testPKI{
+		ca:     ca,
+		server: server,
+		client: client,
+	}
+}
+
+// echoServer answers every request with a response addressed to the requesting player, after checking the token.
+type echoServer struct {
+	deviant.UnimplementedEncounterServiceServer
+	token string
+}
+
+func (s *echoServer) UpdateEncounter(stream deviant.EncounterService_UpdateEncounterServer) error {
+	if s.token != "" {
+		md, _ := metadata.FromIncomingContext(stream.Context())
+		if values := md.Get("authorization"); len(values) != 1 || values[0] != "Bearer "+s.token {
+			return status.Error(codes.Unauthenticated, "missing or invalid token")
+		}
+	}
+
+	for {
+		request, err := stream.Recv()
+		if err != nil {
+			return nil
+		}
+
+		if err := stream.Send(&deviant.EncounterResponse{PlayerId: request.PlayerId}); err != nil {
+			return err
+		}
+	}
+}
+
+func startServer(t *testing.T, server *echoServer, tlsConfig *tls.Config) (string, func()) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	grpcServer := grpc.NewServer(grpc.Creds(credentials.NewTLS(tlsConfig)))
+	deviant.RegisterEncounterServiceServer(grpcServer, server)
+
+	go grpcServer.Serve(listener)
+
+	return listener.Addr().String(), grpcServer.Stop
+}
+
+func roundTrip(config *Config) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := Dial(ctx, config)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	stream, err := deviant.NewEncounterServiceClient(conn).UpdateEncounter(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := stream.Send(&deviant.EncounterRequest{PlayerId: "0001"}); err != nil {
+		return err
+	}
+
+	_, err = stream.Recv()
+
+	return err
+}
+
+func TestDialTLS(t *testing.T) {
+	dir, err := ioutil.TempDir("", "glados-client")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	pki := generatePKI(t)
+	caFile, _ := pki.ca.writeFiles(t, dir, "ca")
+	clientCert, clientKey := pki.client.writeFiles(t, dir, "client")
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(pki.ca.certificate)
+
+	address, stop := startServer(t, &echoServer{token: "secret"}, &tls.Config{
+		Certificates: []tls.Certificate{pki.server.tlsCertificate()},
+		ClientCAs:    clientCAs,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	})
+	defer stop()
+
+	valid := &Config{
+		Address:            address,
+		CAFile:             caFile,
+		CertFile:           clientCert,
+		KeyFile:            clientKey,
+		ServerNameOverride: testServerName,
+		Token:              "secret",
+	}
+
+	if err := roundTrip(valid); err != nil {
+		t.Fatalf("expected a valid configuration to connect: %v", err)
+	}
+
+	wrongToken := *valid
+	wrongToken.Token = "wrong"
+	if err := roundTrip(&wrongToken); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected an invalid token to be rejected, got %v", err)
+	}
+
+	noClientCert := *valid
+	noClientCert.CertFile = ""
+	noClientCert.KeyFile = ""
+	if err := roundTrip(&noClientCert); err == nil {
+		t.Error("expected a missing client certificate to be rejected")
+	}
+
+	wrongServerName := *valid
+	wrongServerName.ServerNameOverride = "other.test"
+	if err := roundTrip(&wrongServerName); err == nil {
+		t.Error("expected a mismatched server name to be rejected")
+	}
+
+	insecure := &Config{Address: address, Token: "secret", InsecureToken: true}
+	if err := roundTrip(insecure); err == nil {
+		t.Error("expected an insecure connection to a TLS server to fail")
+	}
+
+	if _, err := (&Config{Address: address, Token: "secret"}).DialOptions(); err == nil {
+		t.Error("expected a token without TLS to be refused")
+	}
+}
+
+func TestDialPerRPCCredentials(t *testing.T) {
+	pki := generatePKI(t)
+
+	address, stop := startServer(t, &echoServer{token: "from-credentials"}, &tls.Config{
+		Certificates: []tls.Certificate{pki.server.tlsCertificate()},
+	})
+	defer stop()
+
+	dir, err := ioutil.TempDir("", "glados-client")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	caFile, _ := pki.ca.writeFiles(t, dir, "ca")
+
+	config := &Config{
+		Address:            address,
+		CAFile:             caFile,
+		ServerNameOverride: testServerName,
+		PerRPCCredentials:  &bearerToken{token: "from-credentials"},
+	}
+
+	if err := roundTrip(config); err != nil {
+		t.Fatalf("expected per-RPC credentials to be sent: %v", err)
+	}
+}
+
+func TestConfigFromEnvAndFlags(t *testing.T) {
+	for key, value := range map[string]string{
+		EnvAddress:    "staging:443",
+		EnvTLS:        "true",
+		EnvServerName: "staging.test",
+		EnvToken:      "from-env",
+	} {
+		os.Setenv(key, value)
+		defer os.Unsetenv(key)
+	}
+
+	config, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if config.Address != "staging:443" || !config.TLS || config.ServerNameOverride != "staging.test" || config.Token != "from-env" {
+		t.Fatalf("environment was not loaded: %+v", config)
+	}
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	config.RegisterFlags(fs)
+	if err := fs.Parse([]string{"-addr", "other:443", "-token", "from-flag", "-insecure-token"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if config.Address != "other:443" || config.Token != "from-flag" || config.ServerNameOverride != "staging.test" || !config.InsecureToken {
+		t.Fatalf("flags should override the environment: %+v", config)
+	}
+}
+
+func TestConfigFromEnvRejectsInvalidBooleans(t *testing.T) {
+	os.Setenv(EnvTLS, "yes please")
+	defer os.Unsetenv(EnvTLS)
+
+	if _, err := ConfigFromEnv(); err == nil {
+		t.Fatal("expected an invalid " + EnvTLS + " to be rejected")
+	}
+}
+
+func TestTLSConfigRequiresCertificateAndKey(t *testing.T) {
+	if _, err := (&Config{CertFile: "client.crt"}).TLSConfig(); err == nil {
+		t.Fatal("expected an error for a certificate without a key")
+	}
+
+	tlsConfig, err := (&Config{}).TLSConfig()
+	if err != nil || tlsConfig != nil {
+		t.Fatalf("expected TLS to be disabled by default, got %v %v", tlsConfig, err)
+	}
+}
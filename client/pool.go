@@ -0,0 +1,84 @@
+package client
+
+import (
+	"context"
+	"sync"
+
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+	"google.golang.org/grpc"
+)
+
+// Pool shares one connection per distinct server configuration between many bots. gRPC multiplexes every
+// bot's stream over the shared connection and reconnects it transparently.
+type Pool struct {
+	extra []grpc.DialOption
+
+	mu    sync.Mutex
+	conns map[Config]*grpc.ClientConn
+}
+
+// NewPool creates an empty pool that dials with the given options in addition to each configuration's own.
+func NewPool(extra ...grpc.DialOption) *Pool {
+	return &Pool{
+		extra: extra,
+		conns: map[Config]*grpc.ClientConn{},
+	}
+}
+
+// Get returns the connection for config, dialing it on first use. Configurations are compared by value, so any
+// PerRPCCredentials must be comparable, as pointers are.
+func (p *Pool) Get(ctx context.Context, config *Config) (*grpc.ClientConn, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if conn, ok := p.conns[*config]; ok {
+		return conn, nil
+	}
+
+	conn, err := Dial(ctx, config, p.extra...)
+	if err != nil {
+		return nil, err
+	}
+
+	p.conns[*config] = conn
+
+	return conn, nil
+}
+
+// Open opens every stream on the pooled connection for config.
+func (p *Pool) Open(config *Config) OpenFunc {
+	return func(ctx context.Context) (deviant.EncounterService_UpdateEncounterClient, error) {
+		// Dial outside of ctx, the connection outlives the stream that first needed it.
+		conn, err := p.Get(context.Background(), config)
+		if err != nil {
+			return nil, err
+		}
+
+		return Shared(conn)(ctx)
+	}
+}
+
+// Len returns the number of open connections.
+func (p *Pool) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.conns)
+}
+
+// Close closes every pooled connection.
+func (p *Pool) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var firstErr error
+	for config, conn := range p.conns {
+		if err := conn.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+
+		delete(p.conns, config)
+	}
+
+	return firstErr
+}
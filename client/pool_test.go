@@ -0,0 +1,62 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/recluse-games/deviant-glados/internal/fakeserver"
+)
+
+func TestPoolSharesConnections(t *testing.T) {
+	server := &flakyServer{}
+	listener := fakeserver.Serve(server)
+	defer listener.Stop()
+
+	pool := NewPool(listener.DialOption())
+	defer pool.Close()
+
+	first, err := pool.Get(context.Background(), &Config{Address: "bufnet"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := pool.Get(context.Background(), &Config{Address: "bufnet"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if first != second || pool.Len() != 1 {
+		t.Fatal("expected identical configurations to share a connection")
+	}
+
+	if _, err := pool.Get(context.Background(), &Config{Address: "bufnet", Token: "secret", InsecureToken: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	if pool.Len() != 2 {
+		t.Fatalf("expected a separate connection for a different configuration, got %d", pool.Len())
+	}
+
+	for _, playerID := range []string{"0001", "0002"} {
+		stream, err := pool.Open(&Config{Address: "bufnet"})(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := stream.Send(RegistrationRequest(playerID)); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := stream.Recv(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if registrations, _ := server.counts(); registrations != 2 || pool.Len() != 2 {
+		t.Fatalf("expected both streams on the pooled connection, got %d registrations over %d connections", registrations, pool.Len())
+	}
+
+	if err := pool.Close(); err != nil || pool.Len() != 0 {
+		t.Fatalf("expected Close to empty the pool, got %v", err)
+	}
+}
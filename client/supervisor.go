@@ -0,0 +1,258 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/recluse-games/deviant-glados/logging"
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+	"google.golang.org/grpc"
+)
+
+// ErrDisconnected is returned by Send while no stream is open.
+var ErrDisconnected = errors.New("not connected to the encounter server")
+
+// Backoff computes jittered exponential delays between reconnection attempts.
+type Backoff struct {
+	Initial    time.Duration
+	Max        time.Duration
+	Multiplier float64
+	// Jitter is the fraction of each delay that is randomised, between 0 and 1.
+	Jitter float64
+}
+
+// DefaultBackoff the backoff used by a Supervisor without one configured.
+var DefaultBackoff = Backoff{
+	Initial:    250 * time.Millisecond,
+	Max:        30 * time.Second,
+	Multiplier: 2,
+	Jitter:     0.2,
+}
+
+// DefaultCloseTimeout how long a stopping Supervisor waits for the server to end its stream, when none is configured.
+const DefaultCloseTimeout = time.Second
+
+// Delay returns the wait before the given zero based attempt. random should be uniformly distributed in [0, 1).
+func (b Backoff) Delay(attempt int, random float64) time.Duration {
+	delay := float64(b.Initial) * math.Pow(b.Multiplier, float64(attempt))
+	if delay > float64(b.Max) {
+		delay = float64(b.Max)
+	}
+
+	// Spread the delay evenly across [delay * (1 - jitter), delay * (1 + jitter)).
+	delay = delay * (1 - b.Jitter + 2*b.Jitter*random)
+
+	return time.Duration(delay)
+}
+
+// OpenFunc opens a new UpdateEncounter stream. The stream must not outlive ctx.
+type OpenFunc func(ctx context.Context) (deviant.EncounterService_UpdateEncounterClient, error)
+
+// Shared opens every stream on an existing connection.
+func Shared(conn grpc.ClientConnInterface) OpenFunc {
+	return func(ctx context.Context) (deviant.EncounterService_UpdateEncounterClient, error) {
+		return deviant.NewEncounterServiceClient(conn).UpdateEncounter(ctx)
+	}
+}
+
+// Redial dials a fresh connection for every stream and closes it once the stream's context ends.
+func Redial(config *Config, extra ...grpc.DialOption) OpenFunc {
+	return func(ctx context.Context) (deviant.EncounterService_UpdateEncounterClient, error) {
+		conn, err := Dial(ctx, config, extra...)
+		if err != nil {
+			return nil, err
+		}
+
+		go func() {
+			<-ctx.Done()
+			conn.Close()
+		}()
+
+		return deviant.NewEncounterServiceClient(conn).UpdateEncounter(ctx)
+	}
+}
+
+// RegistrationRequest builds the request that adds a player to the players table.
+// HACK: REMOVE THIS ONCE WE HAVE PROPER REGISTRATION.
+func RegistrationRequest(playerID string) *deviant.EncounterRequest {
+	encounterRequest := &deviant.EncounterRequest{}
+	encounterRequest.EncounterCreateAction = &deviant.EncounterCreateAction{}
+	encounterRequest.PlayerId = playerID
+
+	return encounterRequest
+}
+
+// Supervisor keeps an UpdateEncounter stream open for a single player, re-opening it with backoff whenever it
+// drops and registering the player again on every new stream.
+type Supervisor struct {
+	PlayerID string
+	Open     OpenFunc
+	Backoff  Backoff
+	// OnResponse is called from the receiving goroutine for every EncounterResponse.
+	OnResponse func(*deviant.EncounterResponse)
+	// OnReconnect, when not nil, is called with the error every time the stream is lost and about to be opened again.
+	OnReconnect func(err error)
+	// OnSend, when not nil, is called with every request sent, including registrations, and the error sending it.
+	OnSend func(request *deviant.EncounterRequest, err error)
+	// CloseTimeout is how long to wait for the server to end the stream after closing the sending side once Run's
+	// context is done, DefaultCloseTimeout when zero.
+	CloseTimeout time.Duration
+	// Logger receives the supervisor's log entries, nothing is logged when it is nil.
+	Logger logging.Logger
+
+	mu     sync.Mutex
+	stream deviant.EncounterService_UpdateEncounterClient
+}
+
+func (s *Supervisor) log() logging.Logger {
+	return logging.OrNop(s.Logger).With(logging.Player(s.PlayerID))
+}
+
+// Send forwards a request on the current stream.
+func (s *Supervisor) Send(request *deviant.EncounterRequest) error {
+	err := s.send(request)
+
+	if s.OnSend != nil {
+		s.OnSend(request, err)
+	}
+
+	return err
+}
+
+func (s *Supervisor) send(request *deviant.EncounterRequest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stream == nil {
+		return ErrDisconnected
+	}
+
+	return s.stream.Send(request)
+}
+
+func (s *Supervisor) setStream(stream deviant.EncounterService_UpdateEncounterClient) {
+	s.mu.Lock()
+	s.stream = stream
+	s.mu.Unlock()
+}
+
+// closeSend closes the sending side of the current stream so the server sees a clean end to it, reporting whether a
+// stream was open. Nothing can be sent afterwards.
+func (s *Supervisor) closeSend() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stream == nil {
+		return false
+	}
+
+	if err := s.stream.CloseSend(); err != nil {
+		s.log().Warn("Failed to close the encounter stream", logging.Err(err))
+	}
+
+	s.stream = nil
+
+	return true
+}
+
+// session runs a single stream until it fails, reporting whether any response was received. Once ctx is done the
+// stream is closed and given CloseTimeout to end before it is cancelled.
+func (s *Supervisor) session(ctx context.Context) (bool, error) {
+	streamCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	finished := make(chan struct{})
+	defer close(finished)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-finished:
+			return
+		}
+
+		if s.closeSend() {
+			timeout := s.CloseTimeout
+			if timeout <= 0 {
+				timeout = DefaultCloseTimeout
+			}
+
+			select {
+			case <-time.After(timeout):
+			case <-finished:
+			}
+		}
+
+		cancel()
+	}()
+
+	stream, err := s.Open(streamCtx)
+	if err != nil {
+		return false, err
+	}
+
+	s.setStream(stream)
+	defer s.setStream(nil)
+
+	// The stream may have been opened just as ctx ended, after the closing goroutine found nothing to close.
+	if ctx.Err() != nil {
+		s.closeSend()
+	}
+
+	if err := s.Send(RegistrationRequest(s.PlayerID)); err != nil {
+		return false, err
+	}
+
+	received := false
+	for {
+		in, err := stream.Recv()
+		if err != nil {
+			return received, err
+		}
+
+		received = true
+		if s.OnResponse != nil {
+			s.OnResponse(in)
+		}
+	}
+}
+
+// Run keeps the stream open until ctx is done.
+func (s *Supervisor) Run(ctx context.Context) error {
+	backoff := s.Backoff
+	if backoff == (Backoff{}) {
+		backoff = DefaultBackoff
+	}
+
+	attempt := 0
+	for {
+		received, err := s.session(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		// A stream that delivered anything counts as a successful connection, so start backing off afresh.
+		if received {
+			attempt = 0
+		}
+
+		delay := backoff.Delay(attempt, rand.Float64())
+		attempt++
+
+		s.log().Warn("Encounter stream lost, reconnecting", logging.Err(err), logging.Any("delay", delay))
+
+		if s.OnReconnect != nil {
+			s.OnReconnect(err)
+		}
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
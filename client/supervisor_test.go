@@ -0,0 +1,278 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/recluse-games/deviant-glados/internal/fakeserver"
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// flakyServer registers players and then kills every stream after sending a single response.
+type flakyServer struct {
+	deviant.UnimplementedEncounterServiceServer
+
+	mu            sync.Mutex
+	registrations []string
+	requests      []*deviant.EncounterRequest
+}
+
+func (s *flakyServer) UpdateEncounter(stream deviant.EncounterService_UpdateEncounterServer) error {
+	registration, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	if registration.EncounterCreateAction == nil {
+		return status.Error(codes.FailedPrecondition, "expected a registration")
+	}
+
+	s.mu.Lock()
+	s.registrations = append(s.registrations, registration.PlayerId)
+	session := len(s.registrations)
+	s.mu.Unlock()
+
+	if err := stream.Send(&deviant.EncounterResponse{
+		PlayerId:  registration.PlayerId,
+		Encounter: &deviant.Encounter{Id: "encounter_0000", Turn: &deviant.Turn{Id: string(rune('0' + session))}},
+	}); err != nil {
+		return err
+	}
+
+	// Give the client a chance to act on the response before the stream dies.
+	request, err := stream.Recv()
+	if err == nil {
+		s.mu.Lock()
+		s.requests = append(s.requests, request)
+		s.mu.Unlock()
+	}
+
+	return status.Error(codes.Unavailable, "server going away")
+}
+
+func (s *flakyServer) counts() (int, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.registrations), len(s.requests)
+}
+
+// quietServer registers players and waits for them to hang up, noting whether they did so cleanly.
+type quietServer struct {
+	deviant.UnimplementedEncounterServiceServer
+
+	mu         sync.Mutex
+	registered int
+	hangups    []error
+}
+
+func (s *quietServer) UpdateEncounter(stream deviant.EncounterService_UpdateEncounterServer) error {
+	if _, err := stream.Recv(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.registered++
+	s.mu.Unlock()
+
+	for {
+		if _, err := stream.Recv(); err != nil {
+			s.mu.Lock()
+			s.hangups = append(s.hangups, err)
+			s.mu.Unlock()
+
+			if err == io.EOF {
+				return nil
+			}
+
+			return err
+		}
+	}
+}
+
+func (s *quietServer) counts() (int, []error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.registered, append([]error{}, s.hangups...)
+}
+
+func runUntil(t *testing.T, supervisor *Supervisor, done func() bool) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	result := make(chan error, 1)
+	go func() {
+		result <- supervisor.Run(ctx)
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !done() {
+		if time.Now().After(deadline) {
+			cancel()
+			t.Fatal("timed out waiting for the supervisor")
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	cancel()
+	if err := <-result; err != context.Canceled {
+		t.Fatalf("expected Run to stop with the context, got %v", err)
+	}
+}
+
+func TestSupervisorReconnects(t *testing.T) {
+	server := &flakyServer{}
+	listener := fakeserver.Serve(server)
+	defer listener.Stop()
+
+	conn, err := listener.Dial(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	supervisor := &Supervisor{
+		PlayerID: "0001",
+		Open:     Shared(conn),
+		Backoff:  Backoff{Initial: time.Millisecond, Max: 5 * time.Millisecond, Multiplier: 2, Jitter: 0.5},
+	}
+
+	var mu sync.Mutex
+	turns := []string{}
+	reconnects := 0
+	// Sends are noted under their own lock, since the supervisor is sent to while mu is held below.
+	var sentMu sync.Mutex
+	sent := []*deviant.EncounterRequest{}
+	supervisor.OnSend = func(request *deviant.EncounterRequest, err error) {
+		sentMu.Lock()
+		sent = append(sent, request)
+		sentMu.Unlock()
+	}
+	supervisor.OnReconnect = func(error) {
+		mu.Lock()
+		reconnects++
+		mu.Unlock()
+	}
+	supervisor.OnResponse = func(response *deviant.EncounterResponse) {
+		mu.Lock()
+		turns = append(turns, response.Encounter.Turn.Id)
+		mu.Unlock()
+
+		// Act on every response, as the bot would, through whichever stream is current.
+		supervisor.Send(&deviant.EncounterRequest{PlayerId: "0001", EntityActionName: deviant.EntityActionNames_CHANGE_PHASE})
+	}
+
+	runUntil(t, supervisor, func() bool {
+		registrations, requests := server.counts()
+		return registrations >= 3 && requests >= 3
+	})
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	for _, playerID := range server.registrations {
+		if playerID != "0001" {
+			t.Errorf("expected every stream to register player 0001, got %v", playerID)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(turns) < 3 || turns[0] == turns[1] {
+		t.Errorf("expected responses from successive streams, got %v", turns)
+	}
+
+	if reconnects < 2 {
+		t.Errorf("expected every lost stream to be reported, got %d", reconnects)
+	}
+
+	sentMu.Lock()
+	if len(sent) < 6 || sent[0].EncounterCreateAction == nil {
+		t.Errorf("expected every registration and request to be reported as sent, got %v", sent)
+	}
+	sentMu.Unlock()
+
+	if err := supervisor.Send(&deviant.EncounterRequest{}); err != ErrDisconnected {
+		t.Errorf("expected sends after Run returns to fail, got %v", err)
+	}
+}
+
+func TestSupervisorClosesStreamOnStop(t *testing.T) {
+	server := &quietServer{}
+	listener := fakeserver.Serve(server)
+	defer listener.Stop()
+
+	conn, err := listener.Dial(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	supervisor := &Supervisor{PlayerID: "0001", Open: Shared(conn), CloseTimeout: 5 * time.Second}
+
+	runUntil(t, supervisor, func() bool {
+		registered, _ := server.counts()
+		return registered == 1
+	})
+
+	_, hangups := server.counts()
+	if len(hangups) != 1 || hangups[0] != io.EOF {
+		t.Fatalf("expected the server to see the stream closed cleanly, got %v", hangups)
+	}
+}
+
+func TestSupervisorRedials(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := &flakyServer{}
+	grpcServer := grpc.NewServer()
+	deviant.RegisterEncounterServiceServer(grpcServer, server)
+	go grpcServer.Serve(listener)
+	defer grpcServer.Stop()
+
+	supervisor := &Supervisor{
+		PlayerID: "0002",
+		Open:     Redial(&Config{Address: listener.Addr().String()}),
+		Backoff:  Backoff{Initial: time.Millisecond, Max: time.Millisecond, Multiplier: 1},
+	}
+	supervisor.OnResponse = func(response *deviant.EncounterResponse) {
+		supervisor.Send(&deviant.EncounterRequest{PlayerId: "0002", EntityActionName: deviant.EntityActionNames_CHANGE_PHASE})
+	}
+
+	runUntil(t, supervisor, func() bool {
+		registrations, _ := server.counts()
+		return registrations >= 2
+	})
+}
+
+func TestBackoffDelay(t *testing.T) {
+	backoff := Backoff{Initial: 100 * time.Millisecond, Max: time.Second, Multiplier: 2, Jitter: 0.5}
+
+	if delay := backoff.Delay(0, 0.5); delay != 100*time.Millisecond {
+		t.Errorf("expected the first delay to be the initial delay, got %v", delay)
+	}
+
+	if delay := backoff.Delay(2, 0.5); delay != 400*time.Millisecond {
+		t.Errorf("expected the delay to grow exponentially, got %v", delay)
+	}
+
+	if delay := backoff.Delay(10, 0.5); delay != time.Second {
+		t.Errorf("expected the delay to be capped, got %v", delay)
+	}
+
+	if low, high := backoff.Delay(1, 0), backoff.Delay(1, 0.999); low != 100*time.Millisecond || high < 299*time.Millisecond {
+		t.Errorf("expected jitter to spread the delay by half either way, got %v to %v", low, high)
+	}
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/recluse-games/deviant-glados/hunting"
+	"github.com/recluse-games/deviant-glados/logging"
+	"github.com/recluse-games/deviant-glados/render"
+	"github.com/recluse-games/deviant-glados/strategy"
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Supported snapshot formats.
+const (
+	formatAuto   = "auto"
+	formatJSON   = "json"
+	formatBinary = "binary"
+)
+
+type options struct {
+	input      string
+	format     string
+	strategy   string
+	difficulty string
+	seed       int64
+	hunt       string
+	color      bool
+	showTrace  bool
+	hints      int
+	logging    logging.Flags
+	// logger receives the strategy's log entries when it is strategy.Logged, nothing is logged when it is nil.
+	logger logging.Logger
+}
+
+// isResponseJSON reports whether a JSON snapshot is an EncounterResponse rather than a bare Encounter.
+func isResponseJSON(data []byte) bool {
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return false
+	}
+
+	_, ok := fields["encounter"]
+
+	return ok
+}
+
+// decodeSnapshot reads a deviant.Encounter or deviant.EncounterResponse and always returns it as a response.
+func decodeSnapshot(data []byte, format string) (*deviant.EncounterResponse, error) {
+	encounterResponse := &deviant.EncounterResponse{}
+
+	switch format {
+	case formatJSON:
+		if isResponseJSON(data) {
+			if err := protojson.Unmarshal(data, encounterResponse); err != nil {
+				return nil, err
+			}
+		} else {
+			encounterResponse.Encounter = &deviant.Encounter{}
+			if err := protojson.Unmarshal(data, encounterResponse.Encounter); err != nil {
+				return nil, err
+			}
+		}
+	case formatBinary:
+		// A bare encounter decoded as a response either fails on the completed flag's wire type or leaves the
+		// response without an encounter, so fall back to decoding it directly.
+		if err := proto.Unmarshal(data, encounterResponse); err != nil || encounterResponse.Encounter == nil {
+			encounterResponse = &deviant.EncounterResponse{
+				Encounter: &deviant.Encounter{},
+			}
+
+			if err := proto.Unmarshal(data, encounterResponse.Encounter); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+
+	if encounterResponse.Encounter == nil || encounterResponse.Encounter.Board == nil || encounterResponse.Encounter.Board.Entities == nil {
+		return nil, errors.New("snapshot has no board")
+	}
+
+	if encounterResponse.Encounter.ActiveEntity == nil {
+		return nil, errors.New("snapshot has no active entity")
+	}
+
+	return encounterResponse, nil
+}
+
+// loadSnapshot reads a snapshot file, guessing the format from its extension when asked to.
+func loadSnapshot(path string, format string) (*deviant.EncounterResponse, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if format == formatAuto {
+		format = formatBinary
+		if strings.EqualFold(filepath.Ext(path), ".json") {
+			format = formatJSON
+		}
+	}
+
+	return decodeSnapshot(data, format)
+}
+
+func reachableTiles(encounter *deviant.Encounter) []render.Point {
+	points := []render.Point{}
+
+	if hunting.GetEntityVertex(encounter.ActiveEntity, encounter.Board.Entities.Entities) == nil {
+		return points
+	}
+
+	for _, tile := range hunting.GenerateValidMoveVertexes(encounter.ActiveEntity, encounter.Board.Entities.Entities, encounter) {
+		points = append(points, render.Point{X: int(tile.X), Y: int(tile.Y)})
+	}
+
+	return points
+}
+
+func run(opts *options, out io.Writer) error {
+	selected, err := strategy.New(opts.strategy, opts.difficulty, opts.seed)
+	if err != nil {
+		return err
+	}
+
+	if logged, ok := selected.(strategy.Logged); ok && opts.logger != nil {
+		selected = logged.WithLogger(opts.logger)
+	}
+
+	encounterResponse, err := loadSnapshot(opts.input, opts.format)
+	if err != nil {
+		return err
+	}
+
+	encounter := encounterResponse.Encounter
+
+	alignmentToHunt := strategy.OpposingAlignment(encounter.ActiveEntity.Alignment)
+	if opts.hunt != "" {
+		alignment, ok := deviant.Alignment_value[strings.ToUpper(opts.hunt)]
+		if !ok {
+			return fmt.Errorf("unknown alignment %q", opts.hunt)
+		}
+
+		alignmentToHunt = deviant.Alignment(alignment)
+	}
+
+	requests, trace := selected.TakeTurn(encounterResponse, alignmentToHunt)
+
+	fmt.Fprintf(out, "Encounter %s, active entity %s hunting %s with %s\n\n", encounter.Id, encounter.ActiveEntity.Id, alignmentToHunt, opts.strategy)
+
+	boardOptions := render.PlannedTurn(encounter, requests)
+	boardOptions.Reachable = reachableTiles(encounter)
+	boardOptions.Color = opts.color
+	if err := render.Fprint(out, encounter, boardOptions); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "\nPlanned requests:\n")
+	for i, request := range requests {
+		fmt.Fprintf(out, "%2d. %s\n", i+1, protojson.Format(request))
+	}
+
+	if opts.hints > 0 {
+		fmt.Fprintf(out, "\nHints:\n")
+		for i, hint := range hunting.Hints(encounter, alignmentToHunt, opts.hints) {
+			fmt.Fprintf(out, "%2d. %s from (%d, %d) facing %s: %s\n", i+1, hint.CardID, hint.Origin.X, hint.Origin.Y, hint.Rotation, hint.Reason)
+		}
+	}
+
+	if opts.showTrace && trace != nil {
+		fmt.Fprintf(out, "\nDecision trace:\n")
+		if err := trace.WriteJSON(out); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func main() {
+	opts := &options{}
+
+	flag.StringVar(&opts.input, "in", "", "path to an Encounter or EncounterResponse snapshot")
+	flag.StringVar(&opts.format, "format", formatAuto, "snapshot format: auto, json or binary")
+	flag.StringVar(&opts.strategy, "strategy", strategy.Default, fmt.Sprintf("strategy to plan with, one of %v", strategy.Names()))
+	flag.StringVar(&opts.difficulty, "difficulty", strategy.DefaultDifficulty, "how well to play: easy, normal, hard or nightmare")
+	flag.Int64Var(&opts.seed, "seed", 0, "seed for any randomness in the plan, such as the seed recorded in a trace")
+	flag.StringVar(&opts.hunt, "hunt", "", "alignment to hunt, defaults to the opposite of the active entity")
+	flag.BoolVar(&opts.color, "color", false, "colour the board with ANSI escape codes")
+	flag.BoolVar(&opts.showTrace, "trace", true, "print the decision trace")
+	flag.IntVar(&opts.hints, "hints", 0, "print the best n plays for the active entity as player hints")
+	opts.logging.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
+	logger, err := opts.logging.Logger(os.Stderr)
+	if err != nil {
+		log.Fatalf("Invalid logging flags: %v", err)
+	}
+
+	opts.logger = logger
+
+	if opts.input == "" && flag.NArg() > 0 {
+		opts.input = flag.Arg(0)
+	}
+
+	if opts.input == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(opts, os.Stdout); err != nil {
+		logger.Error("Failed to analyze", logging.Any("input", opts.input), logging.Err(err))
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/recluse-games/deviant-glados/internal/fixture"
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+func generateEncounter(completed bool) *deviant.Encounter {
+	encounter := fixture.Slash()
+	encounter.Completed = completed
+
+	return encounter
+}
+
+func writeSnapshot(t *testing.T, dir string, name string, data []byte) string {
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestLoadSnapshot(t *testing.T) {
+	dir, err := ioutil.TempDir("", "glados-analyze")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, completed := range []bool{false, true} {
+		encounter := generateEncounter(completed)
+		response := &deviant.EncounterResponse{PlayerId: "0001", Encounter: encounter}
+
+		encounterJSON, _ := protojson.Marshal(encounter)
+		responseJSON, _ := protojson.Marshal(response)
+		encounterBinary, _ := proto.Marshal(encounter)
+		responseBinary, _ := proto.Marshal(response)
+
+		paths := []string{
+			writeSnapshot(t, dir, "encounter.json", encounterJSON),
+			writeSnapshot(t, dir, "response.json", responseJSON),
+			writeSnapshot(t, dir, "encounter.pb", encounterBinary),
+			writeSnapshot(t, dir, "response.pb", responseBinary),
+		}
+
+		for _, path := range paths {
+			loaded, err := loadSnapshot(path, formatAuto)
+			if err != nil {
+				t.Fatalf("failed to load %s: %v", path, err)
+			}
+
+			if !proto.Equal(loaded.Encounter, encounter) {
+				t.Errorf("%s did not round trip: %v", path, loaded.Encounter)
+			}
+		}
+	}
+}
+
+func TestLoadSnapshotRejectsEmptyBoards(t *testing.T) {
+	if _, err := decodeSnapshot([]byte(`{"id": "encounter_0000"}`), formatJSON); err == nil {
+		t.Fatal("expected an error for a snapshot without a board")
+	}
+}
+
+func TestRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "glados-analyze")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	data, _ := protojson.Marshal(generateEncounter(false))
+	out := &bytes.Buffer{}
+
+	err = run(&options{
+		input:     writeSnapshot(t, dir, "encounter.json", data),
+		format:    formatAuto,
+		strategy:  "hunting",
+		showTrace: true,
+		hints:     1,
+	}, out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{"hunting UNFRIENDLY", "0002:4", "Planned requests:", "entityPlayAction", "Decision trace:", `"kind": "play_card"`, "Hints:", "hits 0002 for 2"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("expected %q in output:\n%s", expected, out.String())
+		}
+	}
+}
@@ -0,0 +1,80 @@
+// Command glados-arena plays a self-play tournament between strategies on generated boards and reports how they
+// rank. It needs no server, so it can run in CI to check that a change makes the bots stronger.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/recluse-games/deviant-glados/arena"
+	"github.com/recluse-games/deviant-glados/encgen"
+)
+
+type options struct {
+	contestants string
+	games       int
+	maxTurns    int
+	teamSize    int
+	obstacles   float64
+	seed        int64
+	parallel    int
+}
+
+func run(opts *options, out io.Writer) error {
+	config := &arena.Config{
+		Games:    opts.games,
+		MaxTurns: opts.maxTurns,
+		Board:    encgen.DefaultConfig(),
+		Seed:     opts.seed,
+		Parallel: opts.parallel,
+	}
+
+	config.Board.Friendly = opts.teamSize
+	config.Board.Unfriendly = opts.teamSize
+	config.Board.Obstacles = opts.obstacles
+
+	for _, spec := range strings.Split(opts.contestants, ",") {
+		contestant, err := arena.ParseContestant(strings.TrimSpace(spec))
+		if err != nil {
+			return err
+		}
+
+		config.Contestants = append(config.Contestants, contestant)
+	}
+
+	report, err := arena.Run(config)
+	if err != nil {
+		return err
+	}
+
+	if err := report.Write(out); err != nil {
+		return err
+	}
+
+	// A forfeited turn means a strategy panicked, which no ranking should hide.
+	if report.Failures > 0 {
+		return fmt.Errorf("%d turns failed because a strategy panicked", report.Failures)
+	}
+
+	return nil
+}
+
+func main() {
+	opts := &options{}
+	flag.StringVar(&opts.contestants, "contestants", "hunting:easy,hunting:normal,hunting:hard,hunting:nightmare", "comma separated contestants, each a strategy or strategy:difficulty")
+	flag.IntVar(&opts.games, "games", 100, "games played by each pair of contestants")
+	flag.IntVar(&opts.maxTurns, "max-turns", 200, "turns after which a game is drawn")
+	flag.IntVar(&opts.teamSize, "team", 2, "entities on each side of the board")
+	flag.Float64Var(&opts.obstacles, "obstacles", encgen.DefaultConfig().Obstacles, "fraction of the tiles between the teams that hold walls")
+	flag.Int64Var(&opts.seed, "seed", 1, "seed for the boards and the contestants' play")
+	flag.IntVar(&opts.parallel, "parallel", 0, "games simulated at once, the number of CPUs when 0")
+	flag.Parse()
+
+	if err := run(opts, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "Tournament failed: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	out := &bytes.Buffer{}
+	opts := &options{contestants: "hunting:easy, hunting:nightmare", games: 4, maxTurns: 100, teamSize: 1, seed: 1}
+
+	if err := run(opts, out); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{"hunting:easy", "hunting:nightmare", "4 games"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("expected %q in the report:\n%s", expected, out)
+		}
+	}
+}
+
+func TestRunRejectsUnknownContestants(t *testing.T) {
+	opts := &options{contestants: "hunting,bogus", games: 4, maxTurns: 100, teamSize: 1, seed: 1}
+
+	if err := run(opts, &bytes.Buffer{}); err == nil {
+		t.Error("expected an error for an unknown strategy")
+	}
+}
@@ -0,0 +1,120 @@
+// Command glados-replay replays matches recorded with the bot's -record flag against the current code, without a
+// server, and reports the first request that differs from the recording.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/recluse-games/deviant-glados/bot"
+	"github.com/recluse-games/deviant-glados/record"
+	"github.com/recluse-games/deviant-glados/replay"
+	"github.com/recluse-games/deviant-glados/strategy"
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+)
+
+// errDiverged is returned by run when any recording was not replayed exactly.
+var errDiverged = errors.New("the replay diverged from the recording")
+
+type options struct {
+	recordings []string
+	playerID   string
+	strategy   string
+	difficulty string
+	seed       int64
+	hunt       string
+	ackTimeout time.Duration
+	wait       time.Duration
+	settle     time.Duration
+}
+
+func run(opts *options, out io.Writer) error {
+	selected, err := strategy.New(opts.strategy, opts.difficulty, opts.seed)
+	if err != nil {
+		return err
+	}
+
+	var hunt *deviant.Alignment
+	if opts.hunt != "" {
+		value, ok := deviant.Alignment_value[strings.ToUpper(opts.hunt)]
+		if !ok {
+			return fmt.Errorf("unknown alignment %q", opts.hunt)
+		}
+
+		alignment := deviant.Alignment(value)
+		hunt = &alignment
+	}
+
+	diverged := false
+	for _, path := range opts.recordings {
+		entries, err := record.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		result, err := replay.Run(context.Background(), entries, &replay.Config{
+			PlayerID:   opts.playerID,
+			Plan:       bot.StrategyPlanner(selected, hunt, nil),
+			AckTimeout: opts.ackTimeout,
+			Wait:       opts.wait,
+			Settle:     opts.settle,
+		})
+		if err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+
+		if result.Divergence == nil {
+			fmt.Fprintf(out, "%s: all %d requests sent as recorded\n", path, result.Requests)
+			continue
+		}
+
+		diverged = true
+
+		entry := "after the recording ended"
+		if result.Divergence.Index < len(entries) {
+			entry = fmt.Sprintf("at %s", entries[result.Divergence.Index].Time.Format(time.RFC3339Nano))
+		}
+
+		fmt.Fprintf(out, "%s: diverged after %d of %d requests, %s\n%s\n", path, result.Matched, result.Requests, entry, result.Divergence)
+	}
+
+	if diverged {
+		return errDiverged
+	}
+
+	return nil
+}
+
+func main() {
+	opts := &options{}
+	flag.StringVar(&opts.playerID, "id", "", "the recorded player, taken from the recording when empty")
+	flag.StringVar(&opts.strategy, "strategy", strategy.Default, fmt.Sprintf("strategy to replay with, one of %v", strategy.Names()))
+	flag.StringVar(&opts.difficulty, "difficulty", strategy.DefaultDifficulty, "difficulty the recorded bot played at")
+	flag.Int64Var(&opts.seed, "seed", 0, "seed the recorded bot played with")
+	flag.StringVar(&opts.hunt, "hunt", "", "alignment to hunt, defaults to the opposite of the active entity")
+	flag.DurationVar(&opts.ackTimeout, "ack-timeout", 5*time.Second, "acknowledgement timeout the recorded bot ran with")
+	flag.DurationVar(&opts.wait, "wait", replay.DefaultWait, "how long to wait for each recorded request")
+	flag.DurationVar(&opts.settle, "settle", replay.DefaultSettle, "how long to wait for requests that were never recorded once a recording ends")
+	flag.Parse()
+
+	opts.recordings = flag.Args()
+	if len(opts.recordings) == 0 {
+		fmt.Fprintf(os.Stderr, "usage: glados-replay [flags] recording.jsonl...\n")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+
+	if err := run(opts, os.Stdout); err != nil {
+		if err != errDiverged {
+			fmt.Fprintf(os.Stderr, "Replay failed: %v\n", err)
+		}
+
+		os.Exit(1)
+	}
+}
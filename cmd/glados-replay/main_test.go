@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/recluse-games/deviant-glados/encgen"
+	"github.com/recluse-games/deviant-glados/record"
+	"github.com/recluse-games/deviant-glados/strategy"
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+)
+
+// writeRecording records the turn the hunting strategy plays on a generated encounter, as changed by edit when it
+// is not nil.
+func writeRecording(t *testing.T, dir string, edit func(requests []*deviant.EncounterRequest)) string {
+	encounter, err := encgen.Generate(encgen.DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	playerID := encounter.ActiveEntity.OwnerId
+	response := &deviant.EncounterResponse{Encounter: encounter}
+
+	selected, err := strategy.New("hunting", "normal", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recorder, err := record.New(dir, playerID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recorder.Received(response)
+
+	requests, _ := selected.TakeTurn(response, strategy.OpposingAlignment(encounter.ActiveEntity.Alignment))
+	if edit != nil {
+		edit(requests)
+	}
+
+	for _, request := range requests {
+		request.PlayerId = playerID
+		recorder.Sent(request, nil)
+	}
+
+	if err := recorder.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return filepath.Join(dir, record.FileName(playerID, encounter.Id))
+}
+
+func TestRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "glados-replay")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	opts := &options{
+		recordings: []string{writeRecording(t, dir, nil)},
+		strategy:   "hunting",
+		difficulty: "normal",
+		wait:       5 * time.Second,
+		settle:     100 * time.Millisecond,
+	}
+
+	out := &bytes.Buffer{}
+	if err := run(opts, out); err != nil {
+		t.Fatalf("%v:\n%s", err, out.String())
+	}
+
+	if !strings.Contains(out.String(), "sent as recorded") {
+		t.Errorf("expected the replay to match, got:\n%s", out.String())
+	}
+}
+
+func TestRunReportsDivergence(t *testing.T) {
+	dir, err := ioutil.TempDir("", "glados-replay")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// A recording where the bot ended its turn at once diverges from the turn the bot plans.
+	recording := writeRecording(t, dir, func(requests []*deviant.EncounterRequest) {
+		requests[0] = &deviant.EncounterRequest{EntityActionName: deviant.EntityActionNames_CHANGE_PHASE}
+	})
+
+	opts := &options{
+		recordings: []string{recording},
+		strategy:   "hunting",
+		difficulty: "normal",
+		wait:       time.Second,
+		settle:     100 * time.Millisecond,
+	}
+
+	out := &bytes.Buffer{}
+	if err := run(opts, out); err != errDiverged {
+		t.Fatalf("expected the replay to diverge, got %v:\n%s", err, out.String())
+	}
+
+	if !strings.Contains(out.String(), "diverged after 0 of") {
+		t.Errorf("expected the divergence to be reported, got:\n%s", out.String())
+	}
+}
+
+func TestRunRejectsUnknownAlignments(t *testing.T) {
+	if err := run(&options{strategy: "hunting", difficulty: "normal", hunt: "everyone"}, ioutil.Discard); err == nil {
+		t.Error("expected an unknown alignment to be rejected")
+	}
+}
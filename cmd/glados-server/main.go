@@ -0,0 +1,64 @@
+// Command glados-server serves the GLaDOS strategies over gRPC so that game servers and clients can ask what the
+// AI would do in any encounter.
+package main
+
+import (
+	"flag"
+	"log"
+	"net"
+	"os"
+
+	"github.com/recluse-games/deviant-glados/gladospb"
+	"github.com/recluse-games/deviant-glados/logging"
+	"github.com/recluse-games/deviant-glados/service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// fatal logs err and exits as a failure.
+func fatal(logger logging.Logger, message string, err error) {
+	logger.Error(message, logging.Err(err))
+	os.Exit(1)
+}
+
+func main() {
+	listen := flag.String("listen", ":50052", "address to serve on")
+	certFile := flag.String("tls-cert", "", "PEM server certificate, serving without TLS when empty")
+	keyFile := flag.String("tls-key", "", "PEM server key")
+
+	loggingFlags := &logging.Flags{}
+	loggingFlags.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
+	logger, err := loggingFlags.Logger(os.Stderr)
+	if err != nil {
+		log.Fatalf("Invalid logging flags: %v", err)
+	}
+
+	options := []grpc.ServerOption{}
+	if *certFile != "" || *keyFile != "" {
+		creds, err := credentials.NewServerTLSFromFile(*certFile, *keyFile)
+		if err != nil {
+			fatal(logger, "Failed to load TLS certificate", err)
+		}
+
+		options = append(options, grpc.Creds(creds))
+	}
+
+	listener, err := net.Listen("tcp", *listen)
+	if err != nil {
+		fatal(logger, "Failed to listen", err)
+	}
+
+	glados := service.NewServer()
+	glados.Logger = logger
+
+	server := grpc.NewServer(options...)
+	gladospb.RegisterGladosServiceServer(server, glados)
+
+	logger.Info("Serving GLaDOS", logging.Any("address", listener.Addr()))
+
+	if err := server.Serve(listener); err != nil {
+		fatal(logger, "Server stopped", err)
+	}
+}
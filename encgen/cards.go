@@ -0,0 +1,91 @@
+package encgen
+
+import (
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+	"google.golang.org/protobuf/proto"
+)
+
+func offset(direction deviant.Direction, distance int32) *deviant.Offset {
+	return &deviant.Offset{Direction: direction, Distance: distance}
+}
+
+func pattern(direction deviant.Direction, distance int32, offsets ...*deviant.Offset) *deviant.Pattern {
+	return &deviant.Pattern{Direction: direction, Distance: distance, Offset: offsets}
+}
+
+// cardTemplate a card as it appears in every deck, without its instance id.
+type cardTemplate struct {
+	id       string
+	title    string
+	cost     int32
+	damage   int32
+	patterns []*deviant.Pattern
+}
+
+// catalogue the cards each class draws its deck from, mirroring the server's starter decks.
+var catalogue = map[deviant.Classes][]cardTemplate{
+	deviant.Classes_WARRIOR: {
+		{
+			id: "attack_slash_0000", title: "Slash", cost: 2, damage: 2,
+			patterns: []*deviant.Pattern{
+				pattern(deviant.Direction_DOWN, 3, offset(deviant.Direction_DOWN, 1)),
+			},
+		},
+		{
+			id: "attack_bash_0000", title: "Bash", cost: 3, damage: 2,
+			patterns: []*deviant.Pattern{
+				pattern(deviant.Direction_DOWN, 3, offset(deviant.Direction_DOWN, 1)),
+				pattern(deviant.Direction_DOWN, 1, offset(deviant.Direction_LEFT, 1), offset(deviant.Direction_DOWN, 3)),
+				pattern(deviant.Direction_DOWN, 1, offset(deviant.Direction_RIGHT, 1), offset(deviant.Direction_DOWN, 3)),
+			},
+		},
+	},
+	deviant.Classes_MAGE: {
+		{
+			id: "attack_fireball_0000", title: "Fireball", cost: 2, damage: 2,
+			patterns: []*deviant.Pattern{
+				pattern(deviant.Direction_DOWN, 1, offset(deviant.Direction_DOWN, 3)),
+			},
+		},
+		{
+			id: "attack_searing_touch_0000", title: "Searing Touch", cost: 2, damage: 3,
+			patterns: []*deviant.Pattern{
+				pattern(deviant.Direction_RIGHT, 1, offset(deviant.Direction_UP, 1), offset(deviant.Direction_RIGHT, 1)),
+				pattern(deviant.Direction_RIGHT, 1, offset(deviant.Direction_DOWN, 1), offset(deviant.Direction_RIGHT, 1)),
+				pattern(deviant.Direction_LEFT, 1, offset(deviant.Direction_UP, 1), offset(deviant.Direction_LEFT, 1)),
+				pattern(deviant.Direction_LEFT, 1, offset(deviant.Direction_DOWN, 1), offset(deviant.Direction_LEFT, 1)),
+			},
+		},
+	},
+	deviant.Classes_PRIEST: {
+		{
+			id: "cast_radience_0000", title: "Radience", cost: 1, damage: 1,
+			patterns: []*deviant.Pattern{
+				pattern(deviant.Direction_DOWN, 1, offset(deviant.Direction_DOWN, 1)),
+				pattern(deviant.Direction_DOWN, 1, offset(deviant.Direction_RIGHT, 1)),
+				pattern(deviant.Direction_DOWN, 1, offset(deviant.Direction_LEFT, 1)),
+			},
+		},
+	},
+}
+
+// card returns a new instance of the card, with patterns of its own so that rotating one card never rotates another.
+func (c cardTemplate) card(instanceID string) *deviant.Card {
+	patterns := []*deviant.Pattern{}
+	for _, p := range c.patterns {
+		patterns = append(patterns, proto.Clone(p).(*deviant.Pattern))
+	}
+
+	return &deviant.Card{
+		Id:         c.id,
+		BackId:     "back_0000",
+		InstanceId: instanceID,
+		Title:      c.title,
+		Type:       deviant.CardType_ATTACK,
+		Cost:       c.cost,
+		Damage:     c.damage,
+		Action: &deviant.CardAction{
+			Pattern: patterns,
+		},
+	}
+}
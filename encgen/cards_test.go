@@ -0,0 +1,22 @@
+package encgen
+
+import (
+	"testing"
+
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+)
+
+func TestCardsDoNotSharePatterns(t *testing.T) {
+	slash := catalogue[deviant.Classes_WARRIOR][0]
+
+	first, second := slash.card("slash_0000"), slash.card("slash_0001")
+	first.Action.Pattern[0].Direction = deviant.Direction_UP
+
+	if second.Action.Pattern[0].Direction != deviant.Direction_DOWN || slash.patterns[0].Direction != deviant.Direction_DOWN {
+		t.Error("expected changing one card's pattern to leave the others alone")
+	}
+
+	if first.InstanceId != "slash_0000" || first.Cost != 2 || first.Damage != 2 {
+		t.Errorf("unexpected card %v", first)
+	}
+}
@@ -0,0 +1,398 @@
+// Package encgen generates random encounters for tests, benchmarks and self-play.
+//
+// Generated encounters are always valid: every entity stands on the board with a deck drawn from its class, the
+// friendly team starts at the top of the board and the unfriendly team at the bottom, and obstacles never cut one
+// part of the board off from another. The same configuration always generates the same encounter.
+package encgen
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+)
+
+// Range an inclusive range of values.
+type Range struct {
+	Min int32
+	Max int32
+}
+
+func (r Range) valid() bool {
+	return r.Min >= 1 && r.Min <= r.Max
+}
+
+func (r Range) draw(random *rand.Rand) int32 {
+	return r.Min + random.Int31n(r.Max-r.Min+1)
+}
+
+// Player ids owning each team.
+const (
+	FriendlyPlayerID   = "0001"
+	UnfriendlyPlayerID = "0002"
+)
+
+// Config the parameters of a generated encounter.
+type Config struct {
+	Rows    int
+	Columns int
+	// Friendly and Unfriendly are the number of entities in each team.
+	Friendly   int
+	Unfriendly int
+	// Classes are the classes entities are drawn from with equal probability.
+	Classes []deviant.Classes
+	HP      Range
+	AP      Range
+	// DeckSize is the number of cards each entity owns, HandSize of which start in its hand.
+	DeckSize int
+	HandSize int
+	// Obstacles is the fraction of the tiles between the teams that hold walls.
+	Obstacles float64
+	// Mirror makes the unfriendly team a reflection of the friendly one, with the same classes, HP, AP and cards,
+	// and alternates initiative between the teams starting with the friendly team. The teams must be the same size.
+	Mirror bool
+	Seed   int64
+}
+
+// DefaultConfig returns a configuration for a two against two encounter on the server's standard board.
+func DefaultConfig() Config {
+	return Config{
+		Rows:       9,
+		Columns:    8,
+		Friendly:   2,
+		Unfriendly: 2,
+		Classes:    []deviant.Classes{deviant.Classes_WARRIOR, deviant.Classes_MAGE, deviant.Classes_PRIEST},
+		HP:         Range{Min: 6, Max: 10},
+		AP:         Range{Min: 5, Max: 5},
+		DeckSize:   6,
+		HandSize:   1,
+		Obstacles:  0.1,
+	}
+}
+
+// spawnRows returns the number of rows at each end of the board the teams start in, at least two but never more
+// than half of the board.
+func (c *Config) spawnRows() int {
+	largest := c.Friendly
+	if c.Unfriendly > largest {
+		largest = c.Unfriendly
+	}
+
+	rows := (largest + c.Columns - 1) / c.Columns
+	if rows < 2 {
+		rows = 2
+	}
+
+	if 2*rows > c.Rows {
+		rows = c.Rows / 2
+	}
+
+	return rows
+}
+
+func (c *Config) validate() error {
+	if c.Rows < 2 || c.Columns < 1 {
+		return fmt.Errorf("a %dx%d board is too small", c.Rows, c.Columns)
+	}
+
+	if c.Friendly < 1 || c.Unfriendly < 1 {
+		return errors.New("both teams need at least one entity")
+	}
+
+	if c.Mirror && c.Friendly != c.Unfriendly {
+		return fmt.Errorf("mirrored teams must be the same size, got %d and %d", c.Friendly, c.Unfriendly)
+	}
+
+	if spawnRows := c.spawnRows(); c.Friendly > spawnRows*c.Columns || c.Unfriendly > spawnRows*c.Columns {
+		return fmt.Errorf("teams of %d and %d do not fit on a %dx%d board", c.Friendly, c.Unfriendly, c.Rows, c.Columns)
+	}
+
+	if len(c.Classes) == 0 {
+		return errors.New("no classes to draw entities from")
+	}
+
+	for _, class := range c.Classes {
+		if len(catalogue[class]) == 0 {
+			return fmt.Errorf("no cards for class %s", class)
+		}
+	}
+
+	if !c.HP.valid() || !c.AP.valid() {
+		return fmt.Errorf("invalid hp %v or ap %v range", c.HP, c.AP)
+	}
+
+	if c.HandSize < 0 || c.DeckSize < c.HandSize {
+		return fmt.Errorf("a hand of %d does not fit in a deck of %d", c.HandSize, c.DeckSize)
+	}
+
+	if c.Obstacles < 0 || c.Obstacles >= 1 {
+		return fmt.Errorf("obstacle density %v is outside [0, 1)", c.Obstacles)
+	}
+
+	return nil
+}
+
+// generator the state of a single generated encounter.
+type generator struct {
+	config *Config
+	random *rand.Rand
+	rows   []*deviant.EntitiesRow
+	count  int
+}
+
+func (g *generator) nextID() string {
+	g.count++
+	return fmt.Sprintf("%04d", g.count)
+}
+
+func (g *generator) at(x int, y int) *deviant.Entity {
+	return g.rows[x].Entities[y]
+}
+
+// blueprint the random attributes of an entity, shared by an entity and its reflection when mirroring.
+type blueprint struct {
+	x, y  int
+	class deviant.Classes
+	hp    int32
+	ap    int32
+	cards []cardTemplate
+}
+
+func (g *generator) blueprints(size int) []*blueprint {
+	tiles := g.random.Perm(g.config.spawnRows() * g.config.Columns)
+	blueprints := []*blueprint{}
+
+	for i := 0; i < size; i++ {
+		class := g.config.Classes[g.random.Intn(len(g.config.Classes))]
+
+		b := &blueprint{
+			x:     tiles[i] / g.config.Columns,
+			y:     tiles[i] % g.config.Columns,
+			class: class,
+			hp:    g.config.HP.draw(g.random),
+			ap:    g.config.AP.draw(g.random),
+		}
+
+		for len(b.cards) < g.config.DeckSize {
+			b.cards = append(b.cards, catalogue[class][g.random.Intn(len(catalogue[class]))])
+		}
+
+		blueprints = append(blueprints, b)
+	}
+
+	return blueprints
+}
+
+func (g *generator) entity(b *blueprint, alignment deviant.Alignment, x int) *deviant.Entity {
+	id := g.nextID()
+
+	ownerID := FriendlyPlayerID
+	rotation := deviant.EntityRotationNames_SOUTH
+	if alignment == deviant.Alignment_UNFRIENDLY {
+		ownerID = UnfriendlyPlayerID
+		rotation = deviant.EntityRotationNames_NORTH
+	}
+
+	cards := []*deviant.Card{}
+	for i, template := range b.cards {
+		cards = append(cards, template.card(fmt.Sprintf("%s_%04d", id, i)))
+	}
+
+	entity := &deviant.Entity{
+		Id:        id,
+		Name:      fmt.Sprintf("%s %s", b.class, id),
+		Class:     b.class,
+		Alignment: alignment,
+		OwnerId:   ownerID,
+		Hp:        b.hp,
+		MaxHp:     b.hp,
+		Ap:        b.ap,
+		MaxAp:     b.ap,
+		State:     deviant.EntityStateNames_IDLE,
+		Rotation:  rotation,
+		Hand:      &deviant.Hand{Cards: cards[:g.config.HandSize]},
+		Deck:      &deviant.Deck{Cards: cards[g.config.HandSize:]},
+		Discard:   &deviant.Discard{Cards: []*deviant.Card{}},
+	}
+
+	g.rows[x].Entities[b.y] = entity
+
+	return entity
+}
+
+// connected reports whether every tile without a wall can be reached from every other.
+func (g *generator) connected() bool {
+	type tile struct{ x, y int }
+
+	open := 0
+	var start *tile
+	for x := range g.rows {
+		for y := range g.rows[x].Entities {
+			if g.at(x, y).Class != deviant.Classes_WALL {
+				open++
+				if start == nil {
+					start = &tile{x, y}
+				}
+			}
+		}
+	}
+
+	if start == nil {
+		return true
+	}
+
+	seen := map[tile]bool{*start: true}
+	queue := []tile{*start}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, next := range []tile{{current.x + 1, current.y}, {current.x - 1, current.y}, {current.x, current.y + 1}, {current.x, current.y - 1}} {
+			if next.x < 0 || next.x >= len(g.rows) || next.y < 0 || next.y >= len(g.rows[next.x].Entities) {
+				continue
+			}
+
+			if seen[next] || g.at(next.x, next.y).Class == deviant.Classes_WALL {
+				continue
+			}
+
+			seen[next] = true
+			queue = append(queue, next)
+		}
+	}
+
+	return len(seen) == open
+}
+
+func (g *generator) wall() *deviant.Entity {
+	return &deviant.Entity{
+		Id:        fmt.Sprintf("wall_%s", g.nextID()),
+		Name:      "Wall",
+		Hp:        2,
+		MaxHp:     2,
+		Class:     deviant.Classes_WALL,
+		State:     deviant.EntityStateNames_IDLE,
+		Alignment: deviant.Alignment_NEUTRAL,
+	}
+}
+
+// placeObstacles puts walls on the tiles between the teams, skipping any wall that would cut the board in two.
+func (g *generator) placeObstacles() {
+	first, last := g.config.spawnRows(), g.config.Rows-1-g.config.spawnRows()
+	if first > last {
+		return
+	}
+
+	tiles := g.random.Perm((last - first + 1) * g.config.Columns)
+	remaining := int(g.config.Obstacles * float64(len(tiles)))
+
+	for _, t := range tiles {
+		if remaining <= 0 {
+			return
+		}
+
+		x, y := first+t/g.config.Columns, t%g.config.Columns
+
+		placed := [][2]int{{x, y}}
+		if g.config.Mirror && g.config.Rows-1-x != x {
+			placed = append(placed, [2]int{g.config.Rows - 1 - x, y})
+		}
+
+		free := true
+		for _, p := range placed {
+			free = free && g.at(p[0], p[1]).Id == ""
+		}
+
+		if !free {
+			continue
+		}
+
+		for _, p := range placed {
+			g.rows[p[0]].Entities[p[1]] = g.wall()
+		}
+
+		if !g.connected() {
+			for _, p := range placed {
+				g.rows[p[0]].Entities[p[1]] = &deviant.Entity{}
+			}
+
+			continue
+		}
+
+		remaining -= len(placed)
+	}
+}
+
+// Generate generates an encounter as described by config, with the entity of highest initiative to act first.
+func Generate(config Config) (*deviant.Encounter, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+
+	g := &generator{config: &config, random: rand.New(rand.NewSource(config.Seed))}
+
+	tiles := []*deviant.TilesRow{}
+	for x := 0; x < config.Rows; x++ {
+		row := &deviant.EntitiesRow{}
+		tileRow := &deviant.TilesRow{}
+
+		for y := 0; y < config.Columns; y++ {
+			row.Entities = append(row.Entities, &deviant.Entity{})
+			tileRow.Tiles = append(tileRow.Tiles, &deviant.Tile{Id: "grass_0000"})
+		}
+
+		g.rows = append(g.rows, row)
+		tiles = append(tiles, tileRow)
+	}
+
+	friendlyBlueprints := g.blueprints(config.Friendly)
+	unfriendlyBlueprints := friendlyBlueprints
+	if !config.Mirror {
+		unfriendlyBlueprints = g.blueprints(config.Unfriendly)
+	}
+
+	friendly := []*deviant.Entity{}
+	for _, b := range friendlyBlueprints {
+		friendly = append(friendly, g.entity(b, deviant.Alignment_FRIENDLY, b.x))
+	}
+
+	unfriendly := []*deviant.Entity{}
+	for _, b := range unfriendlyBlueprints {
+		unfriendly = append(unfriendly, g.entity(b, deviant.Alignment_UNFRIENDLY, config.Rows-1-b.x))
+	}
+
+	// Initiative alternates between the teams, starting with a random team unless they are mirrored.
+	teams := [][]*deviant.Entity{friendly, unfriendly}
+	if !config.Mirror && g.random.Intn(2) == 1 {
+		teams[0], teams[1] = teams[1], teams[0]
+	}
+
+	order := []*deviant.Entity{}
+	for i := 0; i < len(friendly) || i < len(unfriendly); i++ {
+		for _, team := range teams {
+			if i < len(team) {
+				order = append(order, team[i])
+			}
+		}
+	}
+
+	activeEntityOrder := []string{}
+	for i, entity := range order {
+		entity.Initiative = int32(len(order) - i)
+		activeEntityOrder = append(activeEntityOrder, entity.Id)
+	}
+
+	g.placeObstacles()
+
+	return &deviant.Encounter{
+		Id:                fmt.Sprintf("encounter_%d", config.Seed),
+		ActiveEntity:      order[0],
+		ActiveEntityOrder: activeEntityOrder,
+		Board: &deviant.Board{
+			Tiles:    &deviant.Tiles{Tiles: tiles},
+			Entities: &deviant.Entities{Entities: g.rows},
+		},
+		Turn: &deviant.Turn{Id: "turn_0000", Phase: deviant.TurnPhaseNames_PHASE_ACTION},
+	}, nil
+}
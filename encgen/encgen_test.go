@@ -0,0 +1,177 @@
+package encgen
+
+import (
+	"testing"
+
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+	"google.golang.org/protobuf/proto"
+)
+
+// entities lists every entity on the board by alignment, leaving out walls.
+func entities(encounter *deviant.Encounter) map[deviant.Alignment][]*deviant.Entity {
+	teams := map[deviant.Alignment][]*deviant.Entity{}
+	for _, row := range encounter.Board.Entities.Entities {
+		for _, entity := range row.Entities {
+			if entity.Id != "" && entity.Class != deviant.Classes_WALL {
+				teams[entity.Alignment] = append(teams[entity.Alignment], entity)
+			}
+		}
+	}
+
+	return teams
+}
+
+func TestGenerate(t *testing.T) {
+	config := DefaultConfig()
+	config.Rows, config.Columns = 12, 10
+	config.Friendly, config.Unfriendly = 3, 5
+	config.HP = Range{Min: 3, Max: 4}
+	config.AP = Range{Min: 2, Max: 6}
+	config.Seed = 41
+
+	encounter, err := Generate(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows := encounter.Board.Entities.Entities
+	if len(rows) != 12 || len(rows[0].Entities) != 10 || len(encounter.Board.Tiles.Tiles) != 12 {
+		t.Fatalf("expected a 12x10 board but got %dx%d", len(rows), len(rows[0].Entities))
+	}
+
+	teams := entities(encounter)
+	if len(teams[deviant.Alignment_FRIENDLY]) != 3 || len(teams[deviant.Alignment_UNFRIENDLY]) != 5 {
+		t.Fatalf("expected teams of 3 and 5 but got %d and %d", len(teams[deviant.Alignment_FRIENDLY]), len(teams[deviant.Alignment_UNFRIENDLY]))
+	}
+
+	initiatives := map[int32]bool{}
+	for _, team := range teams {
+		for _, entity := range team {
+			if entity.Hp < 3 || entity.Hp > 4 || entity.MaxAp < 2 || entity.MaxAp > 6 || entity.Ap != entity.MaxAp {
+				t.Errorf("%s has hp %d and ap %d outside of the configured ranges", entity.Id, entity.Hp, entity.MaxAp)
+			}
+
+			if len(entity.Hand.Cards) != config.HandSize || len(entity.Deck.Cards) != config.DeckSize-config.HandSize {
+				t.Errorf("%s has %d cards in hand and %d in its deck", entity.Id, len(entity.Hand.Cards), len(entity.Deck.Cards))
+			}
+
+			if initiatives[entity.Initiative] {
+				t.Errorf("%s shares initiative %d", entity.Id, entity.Initiative)
+			}
+			initiatives[entity.Initiative] = true
+		}
+	}
+
+	if encounter.ActiveEntity.Initiative != 8 || len(encounter.ActiveEntityOrder) != 8 || encounter.ActiveEntityOrder[0] != encounter.ActiveEntity.Id {
+		t.Errorf("expected the entity with the highest initiative to act first but got %s", encounter.ActiveEntity.Id)
+	}
+}
+
+func TestGenerateIsDeterministic(t *testing.T) {
+	config := DefaultConfig()
+	config.Seed = 41
+
+	first, _ := Generate(config)
+	second, _ := Generate(config)
+	if !proto.Equal(first, second) {
+		t.Error("expected the same configuration to generate the same encounter")
+	}
+
+	config.Seed = 42
+	if third, _ := Generate(config); proto.Equal(first, third) {
+		t.Error("expected a different seed to generate a different encounter")
+	}
+}
+
+func TestGenerateMirror(t *testing.T) {
+	config := DefaultConfig()
+	config.Friendly, config.Unfriendly = 4, 4
+	config.Mirror = true
+
+	for seed := int64(0); seed < 20; seed++ {
+		config.Seed = seed
+
+		encounter, err := Generate(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rows := encounter.Board.Entities.Entities
+		for x, row := range rows {
+			for y, entity := range row.Entities {
+				mirror := rows[len(rows)-1-x].Entities[y]
+				if (entity.Id == "") != (mirror.Id == "") || entity.Class != mirror.Class || entity.Hp != mirror.Hp || entity.MaxAp != mirror.MaxAp {
+					t.Fatalf("seed %d: (%d, %d) is not mirrored", seed, x, y)
+				}
+
+				if entity.OwnerId != "" && entity.Alignment == mirror.Alignment {
+					t.Fatalf("seed %d: %s faces its own team", seed, entity.Id)
+				}
+
+				if entity.Hand != nil && entity.Hand.Cards[0].Id != mirror.Hand.Cards[0].Id {
+					t.Fatalf("seed %d: %s and %s start with different cards", seed, entity.Id, mirror.Id)
+				}
+			}
+		}
+
+		if encounter.ActiveEntity.Alignment != deviant.Alignment_FRIENDLY {
+			t.Errorf("seed %d: expected the friendly team to act first", seed)
+		}
+	}
+}
+
+func TestGenerateKeepsTheBoardConnected(t *testing.T) {
+	config := DefaultConfig()
+	config.Obstacles = 0.9
+
+	for seed := int64(0); seed < 20; seed++ {
+		config.Seed = seed
+
+		encounter, err := Generate(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		g := &generator{rows: encounter.Board.Entities.Entities}
+		if !g.connected() {
+			t.Fatalf("seed %d: the walls cut the board in two", seed)
+		}
+
+		walls := 0
+		for _, row := range g.rows {
+			for _, entity := range row.Entities {
+				if entity.Class == deviant.Classes_WALL {
+					walls++
+				}
+			}
+		}
+
+		if walls == 0 {
+			t.Errorf("seed %d: expected walls between the teams", seed)
+		}
+	}
+}
+
+func TestGenerateRejectsInvalidConfigs(t *testing.T) {
+	tests := map[string]func(*Config){
+		"tiny board":        func(c *Config) { c.Rows = 1 },
+		"empty team":        func(c *Config) { c.Unfriendly = 0 },
+		"uneven mirror":     func(c *Config) { c.Mirror, c.Friendly = true, 3 },
+		"crowded team":      func(c *Config) { c.Friendly = 33 },
+		"no classes":        func(c *Config) { c.Classes = nil },
+		"walls as entities": func(c *Config) { c.Classes = []deviant.Classes{deviant.Classes_WALL} },
+		"empty hp range":    func(c *Config) { c.HP = Range{Min: 5, Max: 4} },
+		"no ap":             func(c *Config) { c.AP = Range{} },
+		"oversized hand":    func(c *Config) { c.HandSize = 7 },
+		"walled in":         func(c *Config) { c.Obstacles = 1 },
+	}
+
+	for name, modify := range tests {
+		config := DefaultConfig()
+		modify(&config)
+
+		if _, err := Generate(config); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,9 @@
+#!/usr/bin/env bash
+
+# Use for local development/manual invocation
+DEVIANT_PROTOBUF=$(go list -m -f '{{.Dir}}' github.com/recluse-games/deviant-protobuf)
+DEVIANT_GO=github.com/recluse-games/deviant-protobuf/genproto/go
+
+protoc --proto_path='gladospb/' --proto_path="$DEVIANT_PROTOBUF/protobuf/" \
+  --go_out=plugins=grpc,paths=source_relative,MEncounter.proto=$DEVIANT_GO,MEncounterService.proto=$DEVIANT_GO:gladospb/ \
+  gladospb/glados.proto
@@ -0,0 +1,1050 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.24.0
+// 	protoc        v3.12.3
+// source: glados.proto
+
+package gladospb
+
+import (
+	context "context"
+	proto "github.com/golang/protobuf/proto"
+	_go "github.com/recluse-games/deviant-protobuf/genproto/go"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// This is a compile-time assertion that a sufficiently up-to-date version
+// of the legacy proto package is being used.
+const _ = proto.ProtoPackageIsVersion4
+
+type SuggestTurnRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Encounter *_go.Encounter `protobuf:"bytes,1,opt,name=encounter,proto3" json:"encounter,omitempty"`
+	// The registered strategy to plan with, the default strategy when empty.
+	Strategy string `protobuf:"bytes,2,opt,name=strategy,proto3" json:"strategy,omitempty"`
+	// How well to play, normal when empty.
+	Difficulty string `protobuf:"bytes,3,opt,name=difficulty,proto3" json:"difficulty,omitempty"`
+	// Seeds any randomness in the plan, so that the same encounter and seed always suggest the same turn.
+	Seed int64 `protobuf:"varint,4,opt,name=seed,proto3" json:"seed,omitempty"`
+}
+
+func (x *SuggestTurnRequest) Reset() {
+	*x = SuggestTurnRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_glados_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SuggestTurnRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SuggestTurnRequest) ProtoMessage() {}
+
+func (x *SuggestTurnRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_glados_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SuggestTurnRequest.ProtoReflect.Descriptor instead.
+func (*SuggestTurnRequest) Descriptor() ([]byte, []int) {
+	return file_glados_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SuggestTurnRequest) GetEncounter() *_go.Encounter {
+	if x != nil {
+		return x.Encounter
+	}
+	return nil
+}
+
+func (x *SuggestTurnRequest) GetStrategy() string {
+	if x != nil {
+		return x.Strategy
+	}
+	return ""
+}
+
+func (x *SuggestTurnRequest) GetDifficulty() string {
+	if x != nil {
+		return x.Difficulty
+	}
+	return ""
+}
+
+func (x *SuggestTurnRequest) GetSeed() int64 {
+	if x != nil {
+		return x.Seed
+	}
+	return 0
+}
+
+type Point struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	X int32 `protobuf:"varint,1,opt,name=x,proto3" json:"x,omitempty"`
+	Y int32 `protobuf:"varint,2,opt,name=y,proto3" json:"y,omitempty"`
+}
+
+func (x *Point) Reset() {
+	*x = Point{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_glados_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Point) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Point) ProtoMessage() {}
+
+func (x *Point) ProtoReflect() protoreflect.Message {
+	mi := &file_glados_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Point.ProtoReflect.Descriptor instead.
+func (*Point) Descriptor() ([]byte, []int) {
+	return file_glados_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Point) GetX() int32 {
+	if x != nil {
+		return x.X
+	}
+	return 0
+}
+
+func (x *Point) GetY() int32 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
+}
+
+// A single scored card play considered while planning.
+type Candidate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CardId         string `protobuf:"bytes,1,opt,name=cardId,proto3" json:"cardId,omitempty"`
+	CardInstanceId string `protobuf:"bytes,2,opt,name=cardInstanceId,proto3" json:"cardInstanceId,omitempty"`
+	Origin         *Point `protobuf:"bytes,3,opt,name=origin,proto3" json:"origin,omitempty"`
+	MoveCost       int32  `protobuf:"varint,4,opt,name=moveCost,proto3" json:"moveCost,omitempty"`
+	Rotation       string `protobuf:"bytes,5,opt,name=rotation,proto3" json:"rotation,omitempty"`
+	Target         *Point `protobuf:"bytes,6,opt,name=target,proto3" json:"target,omitempty"`
+	TargetId       string `protobuf:"bytes,7,opt,name=targetId,proto3" json:"targetId,omitempty"`
+	TargetHp       int32  `protobuf:"varint,8,opt,name=targetHp,proto3" json:"targetHp,omitempty"`
+	Score          int32  `protobuf:"varint,9,opt,name=score,proto3" json:"score,omitempty"`
+}
+
+func (x *Candidate) Reset() {
+	*x = Candidate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_glados_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Candidate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Candidate) ProtoMessage() {}
+
+func (x *Candidate) ProtoReflect() protoreflect.Message {
+	mi := &file_glados_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Candidate.ProtoReflect.Descriptor instead.
+func (*Candidate) Descriptor() ([]byte, []int) {
+	return file_glados_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Candidate) GetCardId() string {
+	if x != nil {
+		return x.CardId
+	}
+	return ""
+}
+
+func (x *Candidate) GetCardInstanceId() string {
+	if x != nil {
+		return x.CardInstanceId
+	}
+	return ""
+}
+
+func (x *Candidate) GetOrigin() *Point {
+	if x != nil {
+		return x.Origin
+	}
+	return nil
+}
+
+func (x *Candidate) GetMoveCost() int32 {
+	if x != nil {
+		return x.MoveCost
+	}
+	return 0
+}
+
+func (x *Candidate) GetRotation() string {
+	if x != nil {
+		return x.Rotation
+	}
+	return ""
+}
+
+func (x *Candidate) GetTarget() *Point {
+	if x != nil {
+		return x.Target
+	}
+	return nil
+}
+
+func (x *Candidate) GetTargetId() string {
+	if x != nil {
+		return x.TargetId
+	}
+	return ""
+}
+
+func (x *Candidate) GetTargetHp() int32 {
+	if x != nil {
+		return x.TargetHp
+	}
+	return 0
+}
+
+func (x *Candidate) GetScore() int32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+type SuggestTurnResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The requests the strategy would send, in order.
+	Requests []*_go.EncounterRequest `protobuf:"bytes,1,rep,name=requests,proto3" json:"requests,omitempty"`
+	// Every candidate considered, best first.
+	Candidates []*Candidate `protobuf:"bytes,2,rep,name=candidates,proto3" json:"candidates,omitempty"`
+	// What the strategy settled on, such as play_card or move_closest.
+	Choice string `protobuf:"bytes,3,opt,name=choice,proto3" json:"choice,omitempty"`
+}
+
+func (x *SuggestTurnResponse) Reset() {
+	*x = SuggestTurnResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_glados_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SuggestTurnResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SuggestTurnResponse) ProtoMessage() {}
+
+func (x *SuggestTurnResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_glados_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SuggestTurnResponse.ProtoReflect.Descriptor instead.
+func (*SuggestTurnResponse) Descriptor() ([]byte, []int) {
+	return file_glados_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SuggestTurnResponse) GetRequests() []*_go.EncounterRequest {
+	if x != nil {
+		return x.Requests
+	}
+	return nil
+}
+
+func (x *SuggestTurnResponse) GetCandidates() []*Candidate {
+	if x != nil {
+		return x.Candidates
+	}
+	return nil
+}
+
+func (x *SuggestTurnResponse) GetChoice() string {
+	if x != nil {
+		return x.Choice
+	}
+	return ""
+}
+
+type SuggestHintsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Encounter *_go.Encounter `protobuf:"bytes,1,opt,name=encounter,proto3" json:"encounter,omitempty"`
+	// The most hints to return, every hint when zero.
+	Limit int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+}
+
+func (x *SuggestHintsRequest) Reset() {
+	*x = SuggestHintsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_glados_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SuggestHintsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SuggestHintsRequest) ProtoMessage() {}
+
+func (x *SuggestHintsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_glados_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SuggestHintsRequest.ProtoReflect.Descriptor instead.
+func (*SuggestHintsRequest) Descriptor() ([]byte, []int) {
+	return file_glados_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SuggestHintsRequest) GetEncounter() *_go.Encounter {
+	if x != nil {
+		return x.Encounter
+	}
+	return nil
+}
+
+func (x *SuggestHintsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// An entity a hinted play would hit.
+type HintTarget struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id       string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name     string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Position *Point `protobuf:"bytes,3,opt,name=position,proto3" json:"position,omitempty"`
+	Damage   int32  `protobuf:"varint,4,opt,name=damage,proto3" json:"damage,omitempty"`
+	Kills    bool   `protobuf:"varint,5,opt,name=kills,proto3" json:"kills,omitempty"`
+	// Whether the target is of the hunted alignment.
+	Enemy bool `protobuf:"varint,6,opt,name=enemy,proto3" json:"enemy,omitempty"`
+	// Whether the target fights alongside the active entity.
+	Ally bool `protobuf:"varint,7,opt,name=ally,proto3" json:"ally,omitempty"`
+}
+
+func (x *HintTarget) Reset() {
+	*x = HintTarget{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_glados_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HintTarget) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HintTarget) ProtoMessage() {}
+
+func (x *HintTarget) ProtoReflect() protoreflect.Message {
+	mi := &file_glados_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HintTarget.ProtoReflect.Descriptor instead.
+func (*HintTarget) Descriptor() ([]byte, []int) {
+	return file_glados_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *HintTarget) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *HintTarget) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *HintTarget) GetPosition() *Point {
+	if x != nil {
+		return x.Position
+	}
+	return nil
+}
+
+func (x *HintTarget) GetDamage() int32 {
+	if x != nil {
+		return x.Damage
+	}
+	return 0
+}
+
+func (x *HintTarget) GetKills() bool {
+	if x != nil {
+		return x.Kills
+	}
+	return false
+}
+
+func (x *HintTarget) GetEnemy() bool {
+	if x != nil {
+		return x.Enemy
+	}
+	return false
+}
+
+func (x *HintTarget) GetAlly() bool {
+	if x != nil {
+		return x.Ally
+	}
+	return false
+}
+
+// A card play suggested to a player.
+type Hint struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CardId         string        `protobuf:"bytes,1,opt,name=cardId,proto3" json:"cardId,omitempty"`
+	CardInstanceId string        `protobuf:"bytes,2,opt,name=cardInstanceId,proto3" json:"cardInstanceId,omitempty"`
+	Origin         *Point        `protobuf:"bytes,3,opt,name=origin,proto3" json:"origin,omitempty"`
+	MoveCost       int32         `protobuf:"varint,4,opt,name=moveCost,proto3" json:"moveCost,omitempty"`
+	Rotation       string        `protobuf:"bytes,5,opt,name=rotation,proto3" json:"rotation,omitempty"`
+	Targets        []*HintTarget `protobuf:"bytes,6,rep,name=targets,proto3" json:"targets,omitempty"`
+	// Damage dealt to the hunted alignment less damage dealt to allies.
+	Score int32 `protobuf:"varint,7,opt,name=score,proto3" json:"score,omitempty"`
+	// Why the play is worth making, such as "kills Ben".
+	Reason string `protobuf:"bytes,8,opt,name=reason,proto3" json:"reason,omitempty"`
+	// The requests that make the play, in order.
+	Requests []*_go.EncounterRequest `protobuf:"bytes,9,rep,name=requests,proto3" json:"requests,omitempty"`
+}
+
+func (x *Hint) Reset() {
+	*x = Hint{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_glados_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Hint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Hint) ProtoMessage() {}
+
+func (x *Hint) ProtoReflect() protoreflect.Message {
+	mi := &file_glados_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Hint.ProtoReflect.Descriptor instead.
+func (*Hint) Descriptor() ([]byte, []int) {
+	return file_glados_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Hint) GetCardId() string {
+	if x != nil {
+		return x.CardId
+	}
+	return ""
+}
+
+func (x *Hint) GetCardInstanceId() string {
+	if x != nil {
+		return x.CardInstanceId
+	}
+	return ""
+}
+
+func (x *Hint) GetOrigin() *Point {
+	if x != nil {
+		return x.Origin
+	}
+	return nil
+}
+
+func (x *Hint) GetMoveCost() int32 {
+	if x != nil {
+		return x.MoveCost
+	}
+	return 0
+}
+
+func (x *Hint) GetRotation() string {
+	if x != nil {
+		return x.Rotation
+	}
+	return ""
+}
+
+func (x *Hint) GetTargets() []*HintTarget {
+	if x != nil {
+		return x.Targets
+	}
+	return nil
+}
+
+func (x *Hint) GetScore() int32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *Hint) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *Hint) GetRequests() []*_go.EncounterRequest {
+	if x != nil {
+		return x.Requests
+	}
+	return nil
+}
+
+type SuggestHintsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The best plays first.
+	Hints []*Hint `protobuf:"bytes,1,rep,name=hints,proto3" json:"hints,omitempty"`
+}
+
+func (x *SuggestHintsResponse) Reset() {
+	*x = SuggestHintsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_glados_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SuggestHintsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SuggestHintsResponse) ProtoMessage() {}
+
+func (x *SuggestHintsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_glados_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SuggestHintsResponse.ProtoReflect.Descriptor instead.
+func (*SuggestHintsResponse) Descriptor() ([]byte, []int) {
+	return file_glados_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SuggestHintsResponse) GetHints() []*Hint {
+	if x != nil {
+		return x.Hints
+	}
+	return nil
+}
+
+var File_glados_proto protoreflect.FileDescriptor
+
+var file_glados_proto_rawDesc = []byte{
+	0x0a, 0x0c, 0x67, 0x6c, 0x61, 0x64, 0x6f, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x06,
+	0x47, 0x6c, 0x61, 0x64, 0x6f, 0x73, 0x1a, 0x0f, 0x45, 0x6e, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x65,
+	0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x16, 0x45, 0x6e, 0x63, 0x6f, 0x75, 0x6e, 0x74,
+	0x65, 0x72, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22,
+	0x96, 0x01, 0x0a, 0x12, 0x53, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x54, 0x75, 0x72, 0x6e, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x30, 0x0a, 0x09, 0x65, 0x6e, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x44, 0x65, 0x76, 0x69,
+	0x61, 0x6e, 0x74, 0x2e, 0x45, 0x6e, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x52, 0x09, 0x65,
+	0x6e, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x74, 0x72, 0x61,
+	0x74, 0x65, 0x67, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x74, 0x72, 0x61,
+	0x74, 0x65, 0x67, 0x79, 0x12, 0x1e, 0x0a, 0x0a, 0x64, 0x69, 0x66, 0x66, 0x69, 0x63, 0x75, 0x6c,
+	0x74, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x69, 0x66, 0x66, 0x69, 0x63,
+	0x75, 0x6c, 0x74, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x65, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x04, 0x73, 0x65, 0x65, 0x64, 0x22, 0x23, 0x0a, 0x05, 0x50, 0x6f, 0x69, 0x6e,
+	0x74, 0x12, 0x0c, 0x0a, 0x01, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x01, 0x78, 0x12,
+	0x0c, 0x0a, 0x01, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x01, 0x79, 0x22, 0x9f, 0x02,
+	0x0a, 0x09, 0x43, 0x61, 0x6e, 0x64, 0x69, 0x64, 0x61, 0x74, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x63,
+	0x61, 0x72, 0x64, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x61, 0x72,
+	0x64, 0x49, 0x64, 0x12, 0x26, 0x0a, 0x0e, 0x63, 0x61, 0x72, 0x64, 0x49, 0x6e, 0x73, 0x74, 0x61,
+	0x6e, 0x63, 0x65, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x63, 0x61, 0x72,
+	0x64, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x49, 0x64, 0x12, 0x25, 0x0a, 0x06, 0x6f,
+	0x72, 0x69, 0x67, 0x69, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x47, 0x6c,
+	0x61, 0x64, 0x6f, 0x73, 0x2e, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x06, 0x6f, 0x72, 0x69, 0x67,
+	0x69, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x6d, 0x6f, 0x76, 0x65, 0x43, 0x6f, 0x73, 0x74, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x6d, 0x6f, 0x76, 0x65, 0x43, 0x6f, 0x73, 0x74, 0x12, 0x1a,
+	0x0a, 0x08, 0x72, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x72, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x25, 0x0a, 0x06, 0x74, 0x61,
+	0x72, 0x67, 0x65, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x47, 0x6c, 0x61,
+	0x64, 0x6f, 0x73, 0x2e, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65,
+	0x74, 0x12, 0x1a, 0x0a, 0x08, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x49, 0x64, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x49, 0x64, 0x12, 0x1a, 0x0a,
+	0x08, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x48, 0x70, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x08, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x48, 0x70, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x63, 0x6f,
+	0x72, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x22,
+	0x97, 0x01, 0x0a, 0x13, 0x53, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x54, 0x75, 0x72, 0x6e, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x35, 0x0a, 0x08, 0x72, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x44, 0x65, 0x76, 0x69,
+	0x61, 0x6e, 0x74, 0x2e, 0x45, 0x6e, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x52, 0x08, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x12, 0x31,
+	0x0a, 0x0a, 0x63, 0x61, 0x6e, 0x64, 0x69, 0x64, 0x61, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x11, 0x2e, 0x47, 0x6c, 0x61, 0x64, 0x6f, 0x73, 0x2e, 0x43, 0x61, 0x6e, 0x64,
+	0x69, 0x64, 0x61, 0x74, 0x65, 0x52, 0x0a, 0x63, 0x61, 0x6e, 0x64, 0x69, 0x64, 0x61, 0x74, 0x65,
+	0x73, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x68, 0x6f, 0x69, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x63, 0x68, 0x6f, 0x69, 0x63, 0x65, 0x22, 0x5d, 0x0a, 0x13, 0x53, 0x75, 0x67,
+	0x67, 0x65, 0x73, 0x74, 0x48, 0x69, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x30, 0x0a, 0x09, 0x65, 0x6e, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x44, 0x65, 0x76, 0x69, 0x61, 0x6e, 0x74, 0x2e, 0x45, 0x6e,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x52, 0x09, 0x65, 0x6e, 0x63, 0x6f, 0x75, 0x6e, 0x74,
+	0x65, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x22, 0xb3, 0x01, 0x0a, 0x0a, 0x48, 0x69, 0x6e,
+	0x74, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x29, 0x0a, 0x08, 0x70,
+	0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e,
+	0x47, 0x6c, 0x61, 0x64, 0x6f, 0x73, 0x2e, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x08, 0x70, 0x6f,
+	0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x61, 0x6d, 0x61, 0x67, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x64, 0x61, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x14,
+	0x0a, 0x05, 0x6b, 0x69, 0x6c, 0x6c, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x6b,
+	0x69, 0x6c, 0x6c, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x6e, 0x65, 0x6d, 0x79, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x05, 0x65, 0x6e, 0x65, 0x6d, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x61, 0x6c,
+	0x6c, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x61, 0x6c, 0x6c, 0x79, 0x22, 0xb8,
+	0x02, 0x0a, 0x04, 0x48, 0x69, 0x6e, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x61, 0x72, 0x64, 0x49,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x61, 0x72, 0x64, 0x49, 0x64, 0x12,
+	0x26, 0x0a, 0x0e, 0x63, 0x61, 0x72, 0x64, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x49,
+	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x63, 0x61, 0x72, 0x64, 0x49, 0x6e, 0x73,
+	0x74, 0x61, 0x6e, 0x63, 0x65, 0x49, 0x64, 0x12, 0x25, 0x0a, 0x06, 0x6f, 0x72, 0x69, 0x67, 0x69,
+	0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x47, 0x6c, 0x61, 0x64, 0x6f, 0x73,
+	0x2e, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x06, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x12, 0x1a,
+	0x0a, 0x08, 0x6d, 0x6f, 0x76, 0x65, 0x43, 0x6f, 0x73, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x08, 0x6d, 0x6f, 0x76, 0x65, 0x43, 0x6f, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x6f,
+	0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x72, 0x6f,
+	0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x2c, 0x0a, 0x07, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74,
+	0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x47, 0x6c, 0x61, 0x64, 0x6f, 0x73,
+	0x2e, 0x48, 0x69, 0x6e, 0x74, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x52, 0x07, 0x74, 0x61, 0x72,
+	0x67, 0x65, 0x74, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65,
+	0x61, 0x73, 0x6f, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73,
+	0x6f, 0x6e, 0x12, 0x35, 0x0a, 0x08, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x18, 0x09,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x44, 0x65, 0x76, 0x69, 0x61, 0x6e, 0x74, 0x2e, 0x45,
+	0x6e, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52,
+	0x08, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x22, 0x3a, 0x0a, 0x14, 0x53, 0x75, 0x67,
+	0x67, 0x65, 0x73, 0x74, 0x48, 0x69, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x22, 0x0a, 0x05, 0x68, 0x69, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x0c, 0x2e, 0x47, 0x6c, 0x61, 0x64, 0x6f, 0x73, 0x2e, 0x48, 0x69, 0x6e, 0x74, 0x52, 0x05,
+	0x68, 0x69, 0x6e, 0x74, 0x73, 0x32, 0xa2, 0x01, 0x0a, 0x0d, 0x47, 0x6c, 0x61, 0x64, 0x6f, 0x73,
+	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x46, 0x0a, 0x0b, 0x53, 0x75, 0x67, 0x67, 0x65,
+	0x73, 0x74, 0x54, 0x75, 0x72, 0x6e, 0x12, 0x1a, 0x2e, 0x47, 0x6c, 0x61, 0x64, 0x6f, 0x73, 0x2e,
+	0x53, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x54, 0x75, 0x72, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x47, 0x6c, 0x61, 0x64, 0x6f, 0x73, 0x2e, 0x53, 0x75, 0x67, 0x67,
+	0x65, 0x73, 0x74, 0x54, 0x75, 0x72, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x49, 0x0a, 0x0c, 0x53, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x48, 0x69, 0x6e, 0x74, 0x73, 0x12,
+	0x1b, 0x2e, 0x47, 0x6c, 0x61, 0x64, 0x6f, 0x73, 0x2e, 0x53, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74,
+	0x48, 0x69, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x47,
+	0x6c, 0x61, 0x64, 0x6f, 0x73, 0x2e, 0x53, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x48, 0x69, 0x6e,
+	0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x32, 0x5a, 0x30, 0x67, 0x69,
+	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x72, 0x65, 0x63, 0x6c, 0x75, 0x73, 0x65,
+	0x2d, 0x67, 0x61, 0x6d, 0x65, 0x73, 0x2f, 0x64, 0x65, 0x76, 0x69, 0x61, 0x6e, 0x74, 0x2d, 0x67,
+	0x6c, 0x61, 0x64, 0x6f, 0x73, 0x2f, 0x67, 0x6c, 0x61, 0x64, 0x6f, 0x73, 0x70, 0x62, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_glados_proto_rawDescOnce sync.Once
+	file_glados_proto_rawDescData = file_glados_proto_rawDesc
+)
+
+func file_glados_proto_rawDescGZIP() []byte {
+	file_glados_proto_rawDescOnce.Do(func() {
+		file_glados_proto_rawDescData = protoimpl.X.CompressGZIP(file_glados_proto_rawDescData)
+	})
+	return file_glados_proto_rawDescData
+}
+
+var file_glados_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_glados_proto_goTypes = []interface{}{
+	(*SuggestTurnRequest)(nil),   // 0: Glados.SuggestTurnRequest
+	(*Point)(nil),                // 1: Glados.Point
+	(*Candidate)(nil),            // 2: Glados.Candidate
+	(*SuggestTurnResponse)(nil),  // 3: Glados.SuggestTurnResponse
+	(*SuggestHintsRequest)(nil),  // 4: Glados.SuggestHintsRequest
+	(*HintTarget)(nil),           // 5: Glados.HintTarget
+	(*Hint)(nil),                 // 6: Glados.Hint
+	(*SuggestHintsResponse)(nil), // 7: Glados.SuggestHintsResponse
+	(*_go.Encounter)(nil),        // 8: Deviant.Encounter
+	(*_go.EncounterRequest)(nil), // 9: Deviant.EncounterRequest
+}
+var file_glados_proto_depIdxs = []int32{
+	8,  // 0: Glados.SuggestTurnRequest.encounter:type_name -> Deviant.Encounter
+	1,  // 1: Glados.Candidate.origin:type_name -> Glados.Point
+	1,  // 2: Glados.Candidate.target:type_name -> Glados.Point
+	9,  // 3: Glados.SuggestTurnResponse.requests:type_name -> Deviant.EncounterRequest
+	2,  // 4: Glados.SuggestTurnResponse.candidates:type_name -> Glados.Candidate
+	8,  // 5: Glados.SuggestHintsRequest.encounter:type_name -> Deviant.Encounter
+	1,  // 6: Glados.HintTarget.position:type_name -> Glados.Point
+	1,  // 7: Glados.Hint.origin:type_name -> Glados.Point
+	5,  // 8: Glados.Hint.targets:type_name -> Glados.HintTarget
+	9,  // 9: Glados.Hint.requests:type_name -> Deviant.EncounterRequest
+	6,  // 10: Glados.SuggestHintsResponse.hints:type_name -> Glados.Hint
+	0,  // 11: Glados.GladosService.SuggestTurn:input_type -> Glados.SuggestTurnRequest
+	4,  // 12: Glados.GladosService.SuggestHints:input_type -> Glados.SuggestHintsRequest
+	3,  // 13: Glados.GladosService.SuggestTurn:output_type -> Glados.SuggestTurnResponse
+	7,  // 14: Glados.GladosService.SuggestHints:output_type -> Glados.SuggestHintsResponse
+	13, // [13:15] is the sub-list for method output_type
+	11, // [11:13] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_glados_proto_init() }
+func file_glados_proto_init() {
+	if File_glados_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_glados_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SuggestTurnRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_glados_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Point); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_glados_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Candidate); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_glados_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SuggestTurnResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_glados_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SuggestHintsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_glados_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HintTarget); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_glados_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Hint); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_glados_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SuggestHintsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_glados_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_glados_proto_goTypes,
+		DependencyIndexes: file_glados_proto_depIdxs,
+		MessageInfos:      file_glados_proto_msgTypes,
+	}.Build()
+	File_glados_proto = out.File
+	file_glados_proto_rawDesc = nil
+	file_glados_proto_goTypes = nil
+	file_glados_proto_depIdxs = nil
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// GladosServiceClient is the client API for GladosService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type GladosServiceClient interface {
+	// SuggestTurn plans the active entity's turn without playing it.
+	SuggestTurn(ctx context.Context, in *SuggestTurnRequest, opts ...grpc.CallOption) (*SuggestTurnResponse, error)
+	// SuggestHints ranks the card plays open to the active entity as hints for a human player.
+	SuggestHints(ctx context.Context, in *SuggestHintsRequest, opts ...grpc.CallOption) (*SuggestHintsResponse, error)
+}
+
+type gladosServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewGladosServiceClient(cc grpc.ClientConnInterface) GladosServiceClient {
+	return &gladosServiceClient{cc}
+}
+
+func (c *gladosServiceClient) SuggestTurn(ctx context.Context, in *SuggestTurnRequest, opts ...grpc.CallOption) (*SuggestTurnResponse, error) {
+	out := new(SuggestTurnResponse)
+	err := c.cc.Invoke(ctx, "/Glados.GladosService/SuggestTurn", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gladosServiceClient) SuggestHints(ctx context.Context, in *SuggestHintsRequest, opts ...grpc.CallOption) (*SuggestHintsResponse, error) {
+	out := new(SuggestHintsResponse)
+	err := c.cc.Invoke(ctx, "/Glados.GladosService/SuggestHints", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GladosServiceServer is the server API for GladosService service.
+type GladosServiceServer interface {
+	// SuggestTurn plans the active entity's turn without playing it.
+	SuggestTurn(context.Context, *SuggestTurnRequest) (*SuggestTurnResponse, error)
+	// SuggestHints ranks the card plays open to the active entity as hints for a human player.
+	SuggestHints(context.Context, *SuggestHintsRequest) (*SuggestHintsResponse, error)
+}
+
+// UnimplementedGladosServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedGladosServiceServer struct {
+}
+
+func (*UnimplementedGladosServiceServer) SuggestTurn(context.Context, *SuggestTurnRequest) (*SuggestTurnResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SuggestTurn not implemented")
+}
+func (*UnimplementedGladosServiceServer) SuggestHints(context.Context, *SuggestHintsRequest) (*SuggestHintsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SuggestHints not implemented")
+}
+
+func RegisterGladosServiceServer(s *grpc.Server, srv GladosServiceServer) {
+	s.RegisterService(&_GladosService_serviceDesc, srv)
+}
+
+func _GladosService_SuggestTurn_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SuggestTurnRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GladosServiceServer).SuggestTurn(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Glados.GladosService/SuggestTurn",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GladosServiceServer).SuggestTurn(ctx, req.(*SuggestTurnRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GladosService_SuggestHints_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SuggestHintsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GladosServiceServer).SuggestHints(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Glados.GladosService/SuggestHints",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GladosServiceServer).SuggestHints(ctx, req.(*SuggestHintsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _GladosService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "Glados.GladosService",
+	HandlerType: (*GladosServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SuggestTurn",
+			Handler:    _GladosService_SuggestTurn_Handler,
+		},
+		{
+			MethodName: "SuggestHints",
+			Handler:    _GladosService_SuggestHints_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "glados.proto",
+}
@@ -3,16 +3,13 @@ module github.com/recluse-games/deviant-glados
 go 1.14
 
 require (
-	github.com/eapache/channels v1.1.0
-	github.com/eapache/queue v1.1.0 // indirect
-	github.com/golang/glog v0.0.0-20160126235308-23def4e6c14b
-	github.com/golang/protobuf v1.4.2 // indirect
+	github.com/golang/protobuf v1.4.2
 	github.com/google/uuid v1.1.1
+	github.com/prometheus/client_golang v1.7.1
 	github.com/recluse-games/deviant-protobuf v0.0.0-20200605042428-5886b520d06e
 	golang.org/x/net v0.0.0-20200602114024-627f9648deb9 // indirect
-	golang.org/x/sys v0.0.0-20200610111108-226ff32320da // indirect
 	golang.org/x/text v0.3.2 // indirect
 	google.golang.org/genproto v0.0.0-20200610212329-df9b449b0ff2 // indirect
 	google.golang.org/grpc v1.29.1
-	google.golang.org/protobuf v1.24.0 // indirect
+	google.golang.org/protobuf v1.24.0
 )
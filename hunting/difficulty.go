@@ -0,0 +1,199 @@
+package hunting
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+
+	"github.com/recluse-games/deviant-glados/logging"
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+)
+
+// Difficulty how well the hunting strategy plays.
+type Difficulty string
+
+// Supported difficulties, from weakest to strongest.
+const (
+	Easy      Difficulty = "easy"
+	Normal    Difficulty = "normal"
+	Hard      Difficulty = "hard"
+	Nightmare Difficulty = "nightmare"
+)
+
+// Difficulties lists every difficulty from weakest to strongest.
+var Difficulties = []Difficulty{Easy, Normal, Hard, Nightmare}
+
+// ParseDifficulty looks up a difficulty by name, treating an empty name as Normal.
+func ParseDifficulty(name string) (Difficulty, error) {
+	if name == "" {
+		return Normal, nil
+	}
+
+	for _, difficulty := range Difficulties {
+		if string(difficulty) == name {
+			return difficulty, nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown difficulty %q, expected one of %v", name, Difficulties)
+}
+
+// Options tune how a turn is planned. The zero value plays exactly like TakeTurn.
+type Options struct {
+	// Depth is the number of steps planned each turn, where a step is a card play or a move towards the hunted
+	// alignment. Each step is planned against a simulation of the board after the steps before it. Zero and one
+	// both plan a single step.
+	Depth int `json:"depth,omitempty"`
+	// Epsilon is the probability of choosing a uniformly random candidate instead of the best one.
+	Epsilon float64 `json:"epsilon,omitempty"`
+	// Temperature softens the choice between candidates by sampling them in proportion to exp(score/Temperature).
+	// Zero always chooses the best candidate.
+	Temperature float64 `json:"temperature,omitempty"`
+	// ForgetCards is the probability that each card in hand is overlooked for the whole turn.
+	ForgetCards float64 `json:"forgetCards,omitempty"`
+	// RankPlays chooses between plays the way Hints ranks them, preferring kills and counting damage to allies
+	// against a play, rather than hitting the weakest enemy as hard as possible.
+	RankPlays bool `json:"rankPlays,omitempty"`
+	// Seed is mixed with the encounter, turn and active entity to seed each turn's randomness, so that the same
+	// encounter and seed always plan the same turn while consecutive turns still play differently.
+	Seed int64 `json:"seed"`
+	// Logger receives debug entries about planning, nothing is logged when it is nil.
+	Logger logging.Logger `json:"-"`
+
+	random *rand.Rand
+}
+
+// Options returns the planning options for a difficulty.
+func (d Difficulty) Options() Options {
+	switch d {
+	case Easy:
+		return Options{Depth: 1, Epsilon: 0.25, Temperature: 2, ForgetCards: 0.35}
+	case Hard:
+		return Options{Depth: 2}
+	case Nightmare:
+		return Options{Depth: 4, RankPlays: true}
+	}
+
+	return Options{Depth: 1}
+}
+
+// noisy reports whether the options ever stray from the best candidate.
+func (o *Options) noisy() bool {
+	return o != nil && (o.Epsilon > 0 || o.Temperature > 0)
+}
+
+func (o *Options) ranksPlays() bool {
+	return o != nil && o.RankPlays
+}
+
+func (o *Options) depth() int {
+	if o == nil || o.Depth < 1 {
+		return 1
+	}
+
+	return o.Depth
+}
+
+// forTurn returns a copy of the options whose randomness is seeded for planning the encounter's current turn.
+func (o *Options) forTurn(encounter *deviant.Encounter) *Options {
+	if o == nil {
+		return nil
+	}
+
+	hash := fnv.New64a()
+	fmt.Fprintf(hash, "%d/%s", o.Seed, encounter.Id)
+	if encounter.Turn != nil {
+		fmt.Fprintf(hash, "/%s", encounter.Turn.Id)
+	}
+	if encounter.ActiveEntity != nil {
+		fmt.Fprintf(hash, "/%s", encounter.ActiveEntity.Id)
+	}
+
+	turn := *o
+	turn.random = rand.New(rand.NewSource(int64(hash.Sum64())))
+
+	return &turn
+}
+
+// source returns the options' randomness, seeded from Seed alone when it was not seeded for a turn.
+func (o *Options) source() *rand.Rand {
+	if o.random == nil {
+		o.random = rand.New(rand.NewSource(o.Seed))
+	}
+
+	return o.random
+}
+
+func (o *Options) float64() float64 {
+	return o.source().Float64()
+}
+
+func (o *Options) intn(n int) int {
+	return o.source().Intn(n)
+}
+
+// Ways a candidate can be chosen, recorded in traces.
+const (
+	MethodGreedy  = "greedy"
+	MethodEpsilon = "epsilon"
+	MethodSoftmax = "softmax"
+)
+
+// choose picks a candidate from cardVertexRotationPairs, which must not be empty, returning the index of the
+// greedy choice unless the options add noise.
+func (o *Options) choose(cardVertexRotationPairs []*CardVertexRotationPair, greedy int) (int, string) {
+	if !o.noisy() {
+		return greedy, MethodGreedy
+	}
+
+	if o.Epsilon > 0 && o.float64() < o.Epsilon {
+		return o.intn(len(cardVertexRotationPairs)), MethodEpsilon
+	}
+
+	if o.Temperature <= 0 {
+		return greedy, MethodGreedy
+	}
+
+	best := math.Inf(-1)
+	for _, pair := range cardVertexRotationPairs {
+		best = math.Max(best, float64(pair.damage))
+	}
+
+	// Subtracting the best score keeps the exponentials from overflowing.
+	weights := make([]float64, len(cardVertexRotationPairs))
+	total := 0.0
+	for i, pair := range cardVertexRotationPairs {
+		weights[i] = math.Exp((float64(pair.damage) - best) / o.Temperature)
+		total += weights[i]
+	}
+
+	target := o.float64() * total
+	for i, weight := range weights {
+		target -= weight
+		if target < 0 {
+			return i, MethodSoftmax
+		}
+	}
+
+	return len(weights) - 1, MethodSoftmax
+}
+
+// forgetCards returns the cards the active entity remembers to use this turn.
+func (o *Options) forgetCards(cards []*deviant.Card) ([]*deviant.Card, []*deviant.Card) {
+	if o == nil || o.ForgetCards <= 0 {
+		return cards, nil
+	}
+
+	remembered := []*deviant.Card{}
+	forgotten := []*deviant.Card{}
+	for _, card := range cards {
+		if o.float64() < o.ForgetCards {
+			forgotten = append(forgotten, card)
+		} else {
+			remembered = append(remembered, card)
+		}
+	}
+
+	return remembered, forgotten
+}
@@ -0,0 +1,354 @@
+package hunting
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/recluse-games/deviant-glados/encgen"
+	"github.com/recluse-games/deviant-glados/logging"
+	"github.com/recluse-games/deviant-glados/sim"
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestParseDifficulty(t *testing.T) {
+	tests := map[string]Difficulty{
+		"":          Normal,
+		"easy":      Easy,
+		"normal":    Normal,
+		"hard":      Hard,
+		"nightmare": Nightmare,
+	}
+
+	for name, expected := range tests {
+		difficulty, err := ParseDifficulty(name)
+		if err != nil {
+			t.Fatalf("%q: %v", name, err)
+		}
+
+		if difficulty != expected {
+			t.Errorf("%q: expected %s but got %s", name, expected, difficulty)
+		}
+	}
+
+	if _, err := ParseDifficulty("impossible"); err == nil {
+		t.Error("expected an error for an unknown difficulty")
+	}
+}
+
+func generatePairs(damages ...int) []*CardVertexRotationPair {
+	pairs := []*CardVertexRotationPair{}
+	for _, damage := range damages {
+		pairs = append(pairs, &CardVertexRotationPair{damage: damage})
+	}
+
+	return pairs
+}
+
+func TestChooseWithoutNoise(t *testing.T) {
+	pairs := generatePairs(4, 2, 1)
+
+	for _, options := range []*Options{nil, {}, {Depth: 4}} {
+		if chosen, method := options.choose(pairs, 1); chosen != 1 || method != MethodGreedy {
+			t.Errorf("%+v: expected the greedy choice but got %d by %s", options, chosen, method)
+		}
+	}
+}
+
+func TestChooseWithNoise(t *testing.T) {
+	pairs := generatePairs(4, 2, 0)
+	counts := make([]int, len(pairs))
+	methods := map[string]int{}
+
+	options := &Options{Epsilon: 0.2, Temperature: 1, Seed: 1}
+	for i := 0; i < 10000; i++ {
+		chosen, method := options.choose(pairs, 0)
+		counts[chosen]++
+		methods[method]++
+	}
+
+	// Softmax weighs the candidates 1 : e^-2 : e^-4 and epsilon spreads a fifth of the choices evenly.
+	if counts[0] < 6500 || counts[0] > 8000 {
+		t.Errorf("expected the best candidate to be chosen about 73%% of the time but got %v", counts)
+	}
+
+	if counts[0] <= counts[1] || counts[1] <= counts[2] || counts[2] == 0 {
+		t.Errorf("expected better candidates to be chosen more often but got %v", counts)
+	}
+
+	if methods[MethodEpsilon] < 1800 || methods[MethodEpsilon] > 2200 {
+		t.Errorf("expected about 2000 epsilon choices but got %v", methods)
+	}
+}
+
+func TestForgetCards(t *testing.T) {
+	cards := generateCardLiterals(50, deviant.Classes_WARRIOR)
+
+	remembered, forgotten := (*Options)(nil).forgetCards(cards)
+	if len(remembered) != len(cards) || len(forgotten) != 0 {
+		t.Fatalf("expected nil options to remember every card but forgot %d", len(forgotten))
+	}
+
+	options := &Options{ForgetCards: 0.5, Seed: 1}
+	remembered, forgotten = options.forgetCards(cards)
+	if len(remembered)+len(forgotten) != len(cards) {
+		t.Fatalf("expected %d cards but got %d", len(cards), len(remembered)+len(forgotten))
+	}
+
+	if len(forgotten) < 50 || len(forgotten) > 100 {
+		t.Errorf("expected about half of %d cards to be forgotten but got %d", len(cards), len(forgotten))
+	}
+}
+
+func TestTakeTurnWithOptionsForgetsCards(t *testing.T) {
+	encounter := generateSkirmish()
+	place(encounter, 1, 0, &deviant.Entity{Id: "0002", Name: "Ben", Hp: 1, Alignment: deviant.Alignment_UNFRIENDLY})
+
+	options := &Options{ForgetCards: 1}
+	requests, trace := TakeTurnWithOptions(&deviant.EncounterResponse{Encounter: encounter}, deviant.Alignment_UNFRIENDLY, options)
+
+	for _, request := range requests {
+		if request.EntityPlayAction != nil {
+			t.Fatalf("expected no card to be played but got %v", request)
+		}
+	}
+
+	if len(encounter.ActiveEntity.Hand.Cards) != 1 {
+		t.Error("expected the caller's hand to be left alone")
+	}
+
+	if len(trace.Rejected) != 1 || trace.Rejected[0].Reason != "forgotten" {
+		t.Errorf("expected the forgotten card to be traced but got %+v", trace.Rejected)
+	}
+}
+
+func TestTakeTurnWithOptionsPlansSeveralSteps(t *testing.T) {
+	encounter := generateSkirmish()
+	ian := encounter.ActiveEntity
+	ian.Hand.Cards = append(ian.Hand.Cards, generateSkirmish().ActiveEntity.Hand.Cards[0])
+	ian.Hand.Cards[1].InstanceId = "slash_0001"
+	place(encounter, 1, 0, &deviant.Entity{Id: "0002", Name: "Ben", Hp: 4, Alignment: deviant.Alignment_UNFRIENDLY})
+
+	count := func(options *Options) int {
+		requests, _ := TakeTurnWithOptions(&deviant.EncounterResponse{Encounter: encounter}, deviant.Alignment_UNFRIENDLY, options)
+
+		plays := 0
+		for _, request := range requests {
+			if request.EntityPlayAction != nil {
+				plays++
+			}
+		}
+
+		return plays
+	}
+
+	if plays := count(nil); plays != 1 {
+		t.Errorf("expected a single play by default but got %d", plays)
+	}
+
+	if plays := count(&Options{Depth: 4}); plays != 2 {
+		t.Errorf("expected both slashes to be played but got %d", plays)
+	}
+
+	if len(ian.Hand.Cards) != 2 || ian.Ap != 5 {
+		t.Error("expected the caller's encounter to be left alone")
+	}
+}
+
+func TestTakeTurnWithOptionsRanksPlays(t *testing.T) {
+	encounter := generateSkirmish()
+	place(encounter, 4, 0, &deviant.Entity{Id: "0002", Name: "Ben", Hp: 7, MaxHp: 10, Alignment: deviant.Alignment_UNFRIENDLY})
+	place(encounter, 2, 2, &deviant.Entity{Id: "0003", Name: "Ann", Hp: 8, MaxHp: 10, Alignment: deviant.Alignment_UNFRIENDLY})
+	place(encounter, 3, 2, &deviant.Entity{Id: "0004", Name: "Cat", Hp: 8, MaxHp: 10, Alignment: deviant.Alignment_UNFRIENDLY})
+
+	choose := func(options *Options) *TraceCandidate {
+		_, trace := TakeTurnWithOptions(&deviant.EncounterResponse{Encounter: encounter}, deviant.Alignment_UNFRIENDLY, options)
+		if trace.Choice == nil || trace.Choice.Candidate == nil {
+			t.Fatalf("%+v: expected a card to be played", options)
+		}
+
+		return trace.Choice.Candidate
+	}
+
+	if chosen := choose(&Options{}); chosen.TargetID != "0002" {
+		t.Errorf("expected the weakest enemy to be hit by default, got %+v", chosen)
+	}
+
+	// Hitting Ann and Cat together deals twice the damage of hitting Ben alone.
+	if chosen := choose(&Options{RankPlays: true}); chosen.Origin != (TracePoint{X: 0, Y: 2}) || chosen.Rotation != "NORTH" {
+		t.Errorf("expected Ann and Cat to be hit together, got %+v", chosen)
+	}
+}
+
+func TestTakeTurnWithOptionsIsDeterministic(t *testing.T) {
+	encounter := generateSkirmish()
+	place(encounter, 1, 0, &deviant.Entity{Id: "0002", Name: "Ben", Hp: 4, Alignment: deviant.Alignment_UNFRIENDLY})
+	place(encounter, 3, 3, &deviant.Entity{Id: "0003", Name: "Zed", Hp: 1, Alignment: deviant.Alignment_UNFRIENDLY})
+	encounterResponse := &deviant.EncounterResponse{Encounter: encounter}
+
+	plan := func(seed int64) ([]*deviant.EncounterRequest, *Trace) {
+		options := Easy.Options()
+		options.Seed = seed
+
+		return TakeTurnWithOptions(encounterResponse, deviant.Alignment_UNFRIENDLY, &options)
+	}
+
+	same := func(a []*deviant.EncounterRequest, b []*deviant.EncounterRequest) bool {
+		if len(a) != len(b) {
+			return false
+		}
+
+		for i := range a {
+			if !proto.Equal(a[i], b[i]) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	expected, trace := plan(42)
+	if trace.Options == nil || trace.Options.Seed != 42 || trace.Options.ForgetCards != Easy.Options().ForgetCards {
+		t.Fatalf("expected the options and seed to be traced but got %+v", trace.Options)
+	}
+
+	for i := 0; i < 10; i++ {
+		if requests, _ := plan(42); !same(requests, expected) {
+			t.Fatalf("expected the same seed to plan the same turn, attempt %d differs", i)
+		}
+	}
+
+	varied := false
+	for seed := int64(0); seed < 50 && !varied; seed++ {
+		requests, _ := plan(seed)
+		varied = !same(requests, expected)
+	}
+
+	if !varied {
+		t.Error("expected different seeds to plan different turns")
+	}
+
+	if requests, trace := TakeTurnWithTrace(encounterResponse, deviant.Alignment_UNFRIENDLY); trace.Options != nil || len(requests) == 0 {
+		t.Error("expected turns planned without options to trace no options")
+	}
+}
+
+func TestTakeTurnWithOptionsLogs(t *testing.T) {
+	encounter := generateSkirmish()
+	place(encounter, 1, 0, &deviant.Entity{Id: "0002", Name: "Ben", Hp: 4, Alignment: deviant.Alignment_UNFRIENDLY})
+	encounterResponse := &deviant.EncounterResponse{Encounter: encounter}
+
+	output := &bytes.Buffer{}
+	options := Normal.Options()
+	options.Logger = logging.New(output, logging.Text, logging.Debug)
+
+	requests, trace := TakeTurnWithOptions(encounterResponse, deviant.Alignment_UNFRIENDLY, &options)
+
+	want := fmt.Sprintf("DEBUG Planned turn candidates=%d encounter=%s entity=%s hunt=UNFRIENDLY requests=%d\n", len(trace.Candidates), encounter.Id, encounter.ActiveEntity.Id, len(requests))
+	if got := output.String(); strings.Count(got, "\n") != 1 || !strings.HasSuffix(got, want) {
+		t.Fatalf("expected a single entry ending in %q, got %q", want, got)
+	}
+
+	if trace.Options.Logger != nil {
+		t.Error("expected the logger to be left out of the traced options")
+	}
+
+	// Without a logger nothing is written anywhere.
+	options.Logger = nil
+	TakeTurnWithOptions(encounterResponse, deviant.Alignment_UNFRIENDLY, &options)
+}
+
+func opposing(alignment deviant.Alignment) deviant.Alignment {
+	if alignment == deviant.Alignment_FRIENDLY {
+		return deviant.Alignment_UNFRIENDLY
+	}
+
+	return deviant.Alignment_FRIENDLY
+}
+
+func player(difficulty Difficulty, seed int64) sim.Player {
+	options := difficulty.Options()
+	options.Seed = seed
+
+	return func(encounterResponse *deviant.EncounterResponse) []*deviant.EncounterRequest {
+		requests, _ := TakeTurnWithOptions(encounterResponse, opposing(encounterResponse.Encounter.ActiveEntity.Alignment), &options)
+		return requests
+	}
+}
+
+// wins plays difficulty against opponent on both sides of the board and counts how often difficulty wins.
+func wins(t *testing.T, difficulty Difficulty, opponent Difficulty, matches int) int {
+	won := 0
+
+	for i := 0; i < matches; i++ {
+		side, other := deviant.Alignment_FRIENDLY, deviant.Alignment_UNFRIENDLY
+		if i%2 == 1 {
+			side, other = other, side
+		}
+
+		// Consecutive matches share a board with the sides swapped.
+		config := encgen.DefaultConfig()
+		config.Mirror = true
+		config.Seed = int64(i / 2)
+
+		encounter, err := encgen.Generate(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		match, err := sim.New(encounter)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		result := match.Play(map[deviant.Alignment]sim.Player{
+			side:  player(difficulty, int64(i)),
+			other: player(opponent, int64(matches+i)),
+		}, 200)
+
+		if result.Failures > 0 {
+			t.Fatalf("%s against %s: %d turns failed", difficulty, opponent, result.Failures)
+		}
+
+		if !result.Draw && result.Winner == side {
+			won++
+		}
+	}
+
+	return won
+}
+
+func TestDifficultySelfPlay(t *testing.T) {
+	if testing.Short() {
+		t.Skip("self-play is slow")
+	}
+
+	// Evenly matched players win 120 or more of 200 games less than 0.3% of the time, so winning 60% shows the
+	// stronger difficulty really is stronger rather than lucky.
+	const (
+		matches = 200
+		minimum = 0.6
+	)
+
+	tests := []struct {
+		weaker, stronger Difficulty
+	}{
+		{Easy, Normal},
+		{Normal, Hard},
+		{Hard, Nightmare},
+		{Normal, Nightmare},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%s against %s", test.weaker, test.stronger), func(t *testing.T) {
+			won := wins(t, test.stronger, test.weaker, matches)
+			t.Logf("%s won %d of %d against %s", test.stronger, won, matches, test.weaker)
+
+			if float64(won) < minimum*matches {
+				t.Errorf("expected %s to win at least %.0f%% of its games against %s, won %d of %d", test.stronger, 100*minimum, test.weaker, won, matches)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package hunting
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/recluse-games/deviant-glados/render"
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/golden")
+
+// describeRequest writes a request as a single line that reads well in a diff.
+func describeRequest(request *deviant.EncounterRequest) string {
+	switch {
+	case request.EntityMoveAction != nil:
+		move := request.EntityMoveAction
+		return fmt.Sprintf("move %s from (%d, %d) to (%d, %d)", request.PlayerId, move.StartXPosition, move.StartYPosition, move.FinalXPosition, move.FinalYPosition)
+	case request.EntityPlayAction != nil:
+		hits := []string{}
+		for _, play := range request.EntityPlayAction.Plays {
+			hits = append(hits, fmt.Sprintf("(%d, %d)", play.X, play.Y))
+		}
+
+		return fmt.Sprintf("play %s at %s", request.EntityPlayAction.CardId, strings.Join(hits, " "))
+	case request.EntityTargetAction != nil:
+		tiles := []string{}
+		for _, tile := range request.EntityTargetAction.Tiles {
+			tiles = append(tiles, fmt.Sprintf("(%d, %d)", tile.X, tile.Y))
+		}
+
+		if len(tiles) == 0 {
+			return fmt.Sprintf("clear targets of %s", request.EntityTargetAction.Id)
+		}
+
+		return fmt.Sprintf("target %s for %s", strings.Join(tiles, " "), request.EntityTargetAction.Id)
+	case request.EntityActionName == deviant.EntityActionNames_CHANGE_PHASE:
+		return fmt.Sprintf("end turn of %s", request.PlayerId)
+	}
+
+	return protojson.Format(request)
+}
+
+// describeTurn draws the board with the planned turn on top of it, followed by the requests.
+func describeTurn(encounter *deviant.Encounter, alignment deviant.Alignment, requests []*deviant.EncounterRequest) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s: %s hunting %s with %d ap\n\n", encounter.Id, encounter.ActiveEntity.Id, alignment, encounter.ActiveEntity.Ap)
+	b.WriteString(render.Board(encounter, render.PlannedTurn(encounter, requests)))
+	b.WriteString("\n")
+
+	for i, request := range requests {
+		fmt.Fprintf(&b, "%2d. %s\n", i+1, describeRequest(request))
+	}
+
+	return b.String()
+}
+
+// diffLines returns a line diff of want and got, marking removed lines with - and added lines with +.
+func diffLines(want string, got string) string {
+	a, b := strings.Split(want, "\n"), strings.Split(got, "\n")
+
+	// common[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	common := make([][]int, len(a)+1)
+	for i := range common {
+		common[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				common[i][j] = common[i+1][j+1] + 1
+			case common[i+1][j] >= common[i][j+1]:
+				common[i][j] = common[i+1][j]
+			default:
+				common[i][j] = common[i][j+1]
+			}
+		}
+	}
+
+	var diff strings.Builder
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			fmt.Fprintf(&diff, "  %s\n", a[i])
+			i++
+			j++
+		case j == len(b) || (i < len(a) && common[i+1][j] >= common[i][j+1]):
+			fmt.Fprintf(&diff, "- %s\n", a[i])
+			i++
+		default:
+			fmt.Fprintf(&diff, "+ %s\n", b[j])
+			j++
+		}
+	}
+
+	return diff.String()
+}
+
+func TestDiffLines(t *testing.T) {
+	diff := diffLines("a\nb\nc", "a\nc\nd")
+
+	if diff != "  a\n- b\n  c\n+ d\n" {
+		t.Errorf("unexpected diff:\n%s", diff)
+	}
+}
+
+// TestTakeTurnGolden plans a turn for every encounter in testdata/encounters and compares it with the golden file of
+// the same name in testdata/golden. To add a case, save an encounter as protobuf JSON in testdata/encounters; after
+// an intended change in behaviour, run go test ./hunting -run TestTakeTurnGolden -update and review the new files.
+func TestTakeTurnGolden(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join("testdata", "encounters", "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(paths) == 0 {
+		t.Fatal("no encounters in testdata/encounters")
+	}
+
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), ".json")
+
+		t.Run(name, func(t *testing.T) {
+			data, err := ioutil.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			encounter := &deviant.Encounter{}
+			if err := protojson.Unmarshal(data, encounter); err != nil {
+				t.Fatal(err)
+			}
+
+			alignment := opposing(encounter.ActiveEntity.Alignment)
+			requests := TakeTurn(&deviant.EncounterResponse{Encounter: proto.Clone(encounter).(*deviant.Encounter)}, alignment)
+			got := describeTurn(encounter, alignment, requests)
+
+			golden := filepath.Join("testdata", "golden", name+".golden")
+			if *update {
+				if err := ioutil.WriteFile(golden, []byte(got), 0644); err != nil {
+					t.Fatal(err)
+				}
+
+				return
+			}
+
+			want, err := ioutil.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("%v; run go test ./hunting -run TestTakeTurnGolden -update to create it", err)
+			}
+
+			if string(want) != got {
+				t.Errorf("the planned turn differs from %s, run with -update if the change is intended:\n%s", golden, diffLines(string(want), got))
+			}
+		})
+	}
+}
@@ -0,0 +1,270 @@
+package hunting
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+)
+
+// HintTarget an entity a hinted play would hit.
+type HintTarget struct {
+	ID       string     `json:"id"`
+	Name     string     `json:"name,omitempty"`
+	Position TracePoint `json:"position"`
+	Damage   int32      `json:"damage"`
+	Kills    bool       `json:"kills"`
+	// Enemy is set when the target is of the hunted alignment and Ally when it fights alongside the active entity.
+	// Neither is set for anything else in the way, such as walls.
+	Enemy bool `json:"enemy"`
+	Ally  bool `json:"ally"`
+}
+
+// Hint a play suggested to a player along with a short explanation of why it is worth making.
+type Hint struct {
+	CardID         string        `json:"cardId"`
+	CardInstanceID string        `json:"cardInstanceId"`
+	Origin         TracePoint    `json:"origin"`
+	MoveCost       int           `json:"moveCost"`
+	Rotation       string        `json:"rotation"`
+	Targets        []*HintTarget `json:"targets"`
+	// Score is the damage dealt to the hunted alignment less the damage dealt to allies.
+	Score  int    `json:"score"`
+	Reason string `json:"reason"`
+	// Requests are the requests that make the play.
+	Requests []*deviant.EncounterRequest `json:"-"`
+}
+
+// Kills returns the number of hunted entities the play would kill.
+func (h *Hint) Kills() int {
+	kills := 0
+	for _, target := range h.Targets {
+		if target.Kills && target.Enemy {
+			kills++
+		}
+	}
+
+	return kills
+}
+
+// playKey identifies a single play, which may hit several tiles.
+type playKey struct {
+	instanceID string
+	x, y       int
+	rotation   deviant.EntityRotationNames
+}
+
+func displayName(entity *deviant.Entity) string {
+	if entity.Name != "" {
+		return entity.Name
+	}
+
+	return entity.Id
+}
+
+// describeHits summarises hits on a group of targets, such as "hits Ben for 2" or "hits 2 enemies for 4".
+func describeHits(targets []*HintTarget, group string) string {
+	if len(targets) == 1 {
+		return fmt.Sprintf("hits %s for %d", targets[0].Name, targets[0].Damage)
+	}
+
+	var total int32
+	for _, target := range targets {
+		total += target.Damage
+	}
+
+	return fmt.Sprintf("hits %d %s for %d", len(targets), group, total)
+}
+
+// joinNames lists names as "Ben", "Ben and Ann" or "Ben, Ann and Ian".
+func joinNames(names []string) string {
+	if len(names) == 1 {
+		return names[0]
+	}
+
+	return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1]
+}
+
+func (h *Hint) describe() string {
+	killed := []string{}
+	wounded := []*HintTarget{}
+	allies := []*HintTarget{}
+
+	for _, target := range h.Targets {
+		switch {
+		case target.Ally:
+			allies = append(allies, target)
+		case !target.Enemy:
+		case target.Kills:
+			killed = append(killed, target.Name)
+		default:
+			wounded = append(wounded, target)
+		}
+	}
+
+	parts := []string{}
+	if len(killed) > 0 {
+		parts = append(parts, "kills "+joinNames(killed))
+	}
+
+	if len(wounded) > 0 {
+		parts = append(parts, describeHits(wounded, "enemies"))
+	}
+
+	if len(allies) > 0 {
+		parts = append(parts, "but "+describeHits(allies, "allies"))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// bestRanked returns the index of the candidate making the play Hints ranks first, or fallback when none of them does.
+func bestRanked(encounter *deviant.Encounter, alignmentToHunt deviant.Alignment, cardVertexRotationPairs []*CardVertexRotationPair, fallback int) int {
+	hints := Hints(encounter, alignmentToHunt, 1)
+	if len(hints) == 0 {
+		return fallback
+	}
+
+	best := hints[0]
+	for i, pair := range cardVertexRotationPairs {
+		if pair.cardVertexPair.card.InstanceId == best.CardInstanceID && pair.origin.X == best.Origin.X && pair.origin.Y == best.Origin.Y && pair.rotation.String() == best.Rotation {
+			return i
+		}
+	}
+
+	return fallback
+}
+
+// Hints ranks the plays available to the encounter's active entity and returns the best n of them, or all of them
+// when n is not positive. Plays that kill come first, then plays that deal the most damage, then cheaper moves.
+func Hints(encounter *deviant.Encounter, alignmentToHunt deviant.Alignment, n int) []*Hint {
+	entities := encounter.Board.Entities.Entities
+	activeEntity := encounter.ActiveEntity
+
+	hints := []*Hint{}
+	plays := map[playKey]*Hint{}
+	hit := map[playKey]map[string]bool{}
+
+	for _, pair := range GenerateAllLocationMoveCombinations(activeEntity, entities, encounter) {
+		vertex := pair.cardVertexPair.vertex
+		if vertex.X < 0 || vertex.X >= len(entities) || vertex.Y < 0 || vertex.Y >= len(entities[vertex.X].Entities) {
+			continue
+		}
+
+		target := entities[vertex.X].Entities[vertex.Y]
+		if target.Id == "" || target.Id == activeEntity.Id {
+			continue
+		}
+
+		card := pair.cardVertexPair.card
+		key := playKey{card.InstanceId, pair.origin.X, pair.origin.Y, pair.rotation}
+
+		hint, ok := plays[key]
+		if !ok {
+			hint = &Hint{
+				CardID:         card.Id,
+				CardInstanceID: card.InstanceId,
+				Origin:         TracePoint{X: pair.origin.X, Y: pair.origin.Y},
+				MoveCost:       pair.origin.apCost,
+				Rotation:       pair.rotation.String(),
+				Targets:        []*HintTarget{},
+				Requests: []*deviant.EncounterRequest{
+					GenerateMoveAction(pair, encounter),
+					GenerateTargetAction(pair, encounter),
+					GeneratePlayAction(pair, encounter),
+					GenerateClearTargetAction(encounter),
+				},
+			}
+
+			plays[key] = hint
+			hit[key] = map[string]bool{}
+			hints = append(hints, hint)
+		}
+
+		// Patterns can cover a tile more than once, but each entity only takes the card's damage once.
+		if hit[key][target.Id] {
+			continue
+		}
+
+		hit[key][target.Id] = true
+
+		damage := card.Damage
+		if damage > target.Hp {
+			damage = target.Hp
+		}
+
+		hintTarget := &HintTarget{
+			ID:       target.Id,
+			Name:     displayName(target),
+			Position: TracePoint{X: vertex.X, Y: vertex.Y},
+			Damage:   damage,
+			Kills:    card.Damage >= target.Hp,
+			Enemy:    target.Alignment == alignmentToHunt,
+			Ally:     target.Alignment == activeEntity.Alignment,
+		}
+
+		hint.Targets = append(hint.Targets, hintTarget)
+		switch {
+		case hintTarget.Enemy:
+			hint.Score += int(damage)
+		case hintTarget.Ally:
+			hint.Score -= int(damage)
+		}
+	}
+
+	// Only suggest plays that actually hurt the hunted alignment.
+	worthwhile := []*Hint{}
+	for _, hint := range hints {
+		for _, target := range hint.Targets {
+			if target.Enemy && target.Damage > 0 {
+				worthwhile = append(worthwhile, hint)
+				break
+			}
+		}
+	}
+
+	sort.SliceStable(worthwhile, func(i, j int) bool {
+		if worthwhile[i].Kills() != worthwhile[j].Kills() {
+			return worthwhile[i].Kills() > worthwhile[j].Kills()
+		}
+
+		if worthwhile[i].Score != worthwhile[j].Score {
+			return worthwhile[i].Score > worthwhile[j].Score
+		}
+
+		return worthwhile[i].MoveCost < worthwhile[j].MoveCost
+	})
+
+	// Many tiles and rotations hit the same entities with the same card, so only keep the cheapest of each.
+	distinct := []*Hint{}
+	seen := map[string]bool{}
+	for _, hint := range worthwhile {
+		ids := []string{}
+		for _, target := range hint.Targets {
+			ids = append(ids, target.ID)
+		}
+
+		sort.Strings(ids)
+
+		key := fmt.Sprintf("%s %v", hint.CardInstanceID, ids)
+		if seen[key] {
+			continue
+		}
+
+		seen[key] = true
+		distinct = append(distinct, hint)
+	}
+
+	worthwhile = distinct
+
+	if n > 0 && len(worthwhile) > n {
+		worthwhile = worthwhile[:n]
+	}
+
+	for _, hint := range worthwhile {
+		hint.Reason = hint.describe()
+	}
+
+	return worthwhile
+}
@@ -0,0 +1,163 @@
+package hunting
+
+import (
+	"strings"
+	"testing"
+
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+)
+
+// generateSkirmish builds an empty board with Ian, who carries a single slash, standing in the top left corner.
+func generateSkirmish() *deviant.Encounter {
+	ian := &deviant.Entity{
+		Id:        "0001",
+		Name:      "Ian",
+		Hp:        10,
+		MaxHp:     10,
+		Ap:        5,
+		MaxAp:     5,
+		Alignment: deviant.Alignment_FRIENDLY,
+		OwnerId:   "0001",
+		Hand: &deviant.Hand{
+			Cards: []*deviant.Card{
+				{
+					Id:         "attack_slash_0000",
+					InstanceId: "slash_0000",
+					Cost:       2,
+					Damage:     2,
+					Action: &deviant.CardAction{
+						Pattern: []*deviant.Pattern{
+							{
+								Direction: deviant.Direction_DOWN,
+								Distance:  3,
+								Offset: []*deviant.Offset{
+									{Direction: deviant.Direction_DOWN, Distance: 1},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	rows := []*deviant.EntitiesRow{}
+	for x := 0; x < 9; x++ {
+		row := &deviant.EntitiesRow{}
+		for y := 0; y < 8; y++ {
+			row.Entities = append(row.Entities, &deviant.Entity{})
+		}
+		rows = append(rows, row)
+	}
+
+	rows[0].Entities[0] = ian
+
+	return &deviant.Encounter{
+		Id: "encounter_0000",
+		Board: &deviant.Board{
+			Entities: &deviant.Entities{Entities: rows},
+		},
+		ActiveEntity: ian,
+		Turn:         &deviant.Turn{Id: "turn_0000"},
+	}
+}
+
+func place(encounter *deviant.Encounter, x int, y int, entity *deviant.Entity) {
+	encounter.Board.Entities.Entities[x].Entities[y] = entity
+}
+
+func TestHints(t *testing.T) {
+	skirmish := generateSkirmish()
+	place(skirmish, 4, 0, &deviant.Entity{Id: "0002", Name: "Ben", Hp: 2, MaxHp: 10, Alignment: deviant.Alignment_UNFRIENDLY})
+	place(skirmish, 2, 2, &deviant.Entity{Id: "0003", Name: "Ann", Hp: 8, MaxHp: 10, Alignment: deviant.Alignment_UNFRIENDLY})
+	place(skirmish, 3, 2, &deviant.Entity{Id: "0004", Name: "Cat", Hp: 8, MaxHp: 10, Alignment: deviant.Alignment_UNFRIENDLY})
+
+	hints := Hints(skirmish, deviant.Alignment_UNFRIENDLY, 3)
+	if len(hints) != 3 {
+		t.Fatalf("expected three hints, got %v\n%s", len(hints), renderTurn(skirmish, nil))
+	}
+
+	if hints[0].Reason != "kills Ben" || hints[0].Kills() != 1 {
+		t.Errorf("expected killing Ben to be the best hint, got %q", hints[0].Reason)
+	}
+
+	if hints[1].Reason != "hits 2 enemies for 4" || hints[1].Score != 4 {
+		t.Errorf("expected hitting Ann and Cat together to come next, got %q", hints[1].Reason)
+	}
+
+	if hints[0].MoveCost != 1 {
+		t.Errorf("expected the cheapest way to kill Ben, got a move costing %v", hints[0].MoveCost)
+	}
+
+	for _, hint := range hints {
+		if len(hint.Requests) != 4 || hint.Requests[2].EntityPlayAction == nil {
+			t.Errorf("expected every hint to carry the requests that make it, got %v", hint.Requests)
+		}
+	}
+
+	if all := Hints(skirmish, deviant.Alignment_UNFRIENDLY, 0); len(all) <= len(hints) {
+		t.Errorf("expected every play to be returned without a limit, got %v", len(all))
+	}
+}
+
+func TestHintsWarnAboutAllies(t *testing.T) {
+	skirmish := generateSkirmish()
+	place(skirmish, 4, 0, &deviant.Entity{Id: "0002", Name: "Ben", Hp: 8, MaxHp: 10, Alignment: deviant.Alignment_UNFRIENDLY})
+	place(skirmish, 3, 0, &deviant.Entity{Id: "0003", Name: "Zach", Hp: 8, MaxHp: 10, Alignment: deviant.Alignment_FRIENDLY})
+
+	friendlyFire := false
+	for _, hint := range Hints(skirmish, deviant.Alignment_UNFRIENDLY, 0) {
+		for _, target := range hint.Targets {
+			if !target.Ally {
+				continue
+			}
+
+			friendlyFire = true
+			if !strings.Contains(hint.Reason, "but hits Zach for 2") {
+				t.Errorf("expected friendly fire to be called out, got %q", hint.Reason)
+			}
+		}
+
+		if hint.Kills() != 0 || hint.Score > 2 {
+			t.Errorf("expected no play to do better than wounding Ben, got %q scoring %v", hint.Reason, hint.Score)
+		}
+	}
+
+	if !friendlyFire {
+		t.Error("expected a play that hits both Ben and Zach")
+	}
+}
+
+func TestHintsIgnoreBystanders(t *testing.T) {
+	skirmish := generateSkirmish()
+	place(skirmish, 4, 0, &deviant.Entity{Id: "0002", Name: "Ben", Hp: 8, MaxHp: 10, Alignment: deviant.Alignment_UNFRIENDLY})
+	place(skirmish, 3, 0, &deviant.Entity{Id: "wall_0000", Name: "Wall", Hp: 1, MaxHp: 1, Alignment: deviant.Alignment_NEUTRAL})
+
+	walled := false
+	for _, hint := range Hints(skirmish, deviant.Alignment_UNFRIENDLY, 0) {
+		for _, target := range hint.Targets {
+			if target.ID != "wall_0000" {
+				continue
+			}
+
+			walled = true
+			if target.Ally || target.Enemy {
+				t.Errorf("expected the wall to be neither an ally nor an enemy, got %+v", target)
+			}
+		}
+
+		if hint.Kills() != 0 || strings.Contains(hint.Reason, "Wall") || strings.Contains(hint.Reason, "allies") {
+			t.Errorf("expected the wall to be left out of the hint, got %q", hint.Reason)
+		}
+	}
+
+	if !walled {
+		t.Error("expected a play that hits both Ben and the wall")
+	}
+}
+
+func TestHintsWithoutTargets(t *testing.T) {
+	if hints := Hints(generateSkirmish(), deviant.Alignment_UNFRIENDLY, 3); len(hints) != 0 {
+		t.Fatalf("expected no hints on an empty board, got %v", hints)
+	}
+}
@@ -1,12 +1,13 @@
 package hunting
 
 import (
-	"fmt"
-	"log"
 	"math"
 	"sort"
 
+	"github.com/recluse-games/deviant-glados/logging"
+	"github.com/recluse-games/deviant-glados/sim"
 	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+	"google.golang.org/protobuf/proto"
 )
 
 type manhattenPair struct {
@@ -116,6 +117,13 @@ func floodFill(startx int32, starty int32, x int32, y int32, filledID string, bl
 			apCostY = 0
 		}
 
+		// Tiles that cost more than the available AP cannot be reached, so they are left unfilled. Checking before
+		// filling rather than before recursing keeps tiles one step past the limit out of the moves, and since cost
+		// only grows away from the start nothing beyond them is reachable either.
+		if limit-apCostX-apCostY < 0 {
+			return
+		}
+
 		newTile := &gridNode{}
 		newTile.X = int32(x)
 		newTile.Y = int32(y)
@@ -123,22 +131,20 @@ func floodFill(startx int32, starty int32, x int32, y int32, filledID string, bl
 		newTile.apCost = int(apCostX + apCostY)
 		(*tiles[x])[y] = newTile
 
-		if limit-apCostX-apCostY >= 0 {
-			if x+1 <= 8 {
-				floodFill(startx, starty, x+1, y, filledID, blockedID, limit, tiles)
-			}
+		if int(x+1) < len(tiles) {
+			floodFill(startx, starty, x+1, y, filledID, blockedID, limit, tiles)
+		}
 
-			if y+1 <= 7 {
-				floodFill(startx, starty, x, y+1, filledID, blockedID, limit, tiles)
-			}
+		if int(y+1) < len(*tiles[x]) {
+			floodFill(startx, starty, x, y+1, filledID, blockedID, limit, tiles)
+		}
 
-			if x-1 >= 0 {
-				floodFill(startx, starty, x-1, y, filledID, blockedID, limit, tiles)
-			}
+		if x-1 >= 0 {
+			floodFill(startx, starty, x-1, y, filledID, blockedID, limit, tiles)
+		}
 
-			if y-1 >= 0 {
-				floodFill(startx, starty, x, y-1, filledID, blockedID, limit, tiles)
-			}
+		if y-1 >= 0 {
+			floodFill(startx, starty, x, y-1, filledID, blockedID, limit, tiles)
 		}
 	}
 }
@@ -668,7 +674,13 @@ func GenerateEndTurnAction(encounter *deviant.Encounter) *deviant.EncounterReque
 	return encounterRequest
 }
 
+// GenerateClosestMove Moves the active entity as close as it can to the given alignment, or returns nil when there is
+// nothing of that alignment on the board or nowhere to move.
 func GenerateClosestMove(alignment deviant.Alignment, encounter *deviant.Encounter) *deviant.EncounterRequest {
+	return generateClosestMove(alignment, encounter, nil)
+}
+
+func generateClosestMove(alignment deviant.Alignment, encounter *deviant.Encounter, trace *Trace) *deviant.EncounterRequest {
 
 	manhattenPairs := []*manhattenPair{}
 	entityLocations := GenerateEntityLocationPairs(alignment, encounter.Board.Entities.Entities)
@@ -683,13 +695,16 @@ func GenerateClosestMove(alignment deviant.Alignment, encounter *deviant.Encount
 				Y:        int32(validMove.Y),
 				distance: distance,
 			}
-			log.Output(0, fmt.Sprintf("%v", newManhattenPair))
-
 			manhattenPairs = append(manhattenPairs, newManhattenPair)
 		}
 	}
 
+	if len(manhattenPairs) == 0 {
+		return nil
+	}
+
 	sort.SliceStable(manhattenPairs, func(i, j int) bool { return manhattenPairs[i].distance < manhattenPairs[j].distance })
+	trace.chooseMove(manhattenPairs[0].X, manhattenPairs[0].Y, manhattenPairs[0].distance)
 
 	entityMoveAction := &deviant.EntityMoveAction{
 		StartXPosition: int32(GetEntityVertex(encounter.ActiveEntity, encounter.Board.Entities.Entities).X),
@@ -707,29 +722,104 @@ func GenerateClosestMove(alignment deviant.Alignment, encounter *deviant.Encount
 	return encounterRequest
 }
 
+// TakeTurn Plans the active entity's turn against the given alignment.
 func TakeTurn(encounterResponse *deviant.EncounterResponse, alignmentToHunt deviant.Alignment) []*deviant.EncounterRequest {
+	return takeTurn(encounterResponse, alignmentToHunt, nil, nil)
+}
+
+// TakeTurnWithTrace Plans the active entity's turn and explains how the plan was chosen.
+func TakeTurnWithTrace(encounterResponse *deviant.EncounterResponse, alignmentToHunt deviant.Alignment) ([]*deviant.EncounterRequest, *Trace) {
+	return TakeTurnWithOptions(encounterResponse, alignmentToHunt, nil)
+}
+
+// TakeTurnWithOptions Plans the active entity's turn as tuned by options and explains how the plan was chosen. Nil
+// options play exactly like TakeTurn. The same encounter and options always plan the same turn, and the options are
+// recorded in the trace so that it can be replayed.
+func TakeTurnWithOptions(encounterResponse *deviant.EncounterResponse, alignmentToHunt deviant.Alignment, options *Options) ([]*deviant.EncounterRequest, *Trace) {
+	trace := newTrace(encounterResponse.Encounter, alignmentToHunt)
+	if options != nil {
+		recorded := *options
+		recorded.random = nil
+		recorded.Logger = nil
+		trace.Options = &recorded
+	}
+
+	encounterRequests := takeTurn(encounterResponse, alignmentToHunt, trace, options.forTurn(encounterResponse.Encounter))
+	trace.Requests = encounterRequests
+
+	if options != nil && options.Logger != nil {
+		encounter := encounterResponse.Encounter
+		options.Logger.Debug("Planned turn",
+			logging.Encounter(encounter.Id),
+			logging.Entity(encounter.ActiveEntity.Id),
+			logging.Any("hunt", alignmentToHunt),
+			logging.Any("candidates", len(trace.Candidates)),
+			logging.Any("requests", len(encounterRequests)))
+	}
+
+	return encounterRequests, trace
+}
+
+func takeTurn(encounterResponse *deviant.EncounterResponse, alignmentToHunt deviant.Alignment, trace *Trace, options *Options) []*deviant.EncounterRequest {
 
 	encounterRequests := []*deviant.EncounterRequest{}
+	encounter := encounterResponse.Encounter
 
-	allHittingMoveCombinations := FilterCardPlaysToHits(encounterResponse.Encounter.Board.Entities.Entities, encounterResponse.Encounter, alignmentToHunt)
-	bestMovesInDamageOrder := SortCardPlaysByDamageInflicted(alignmentToHunt, allHittingMoveCombinations, encounterResponse.Encounter.Board.Entities)
-	entityLocationVertexPairs := GenerateEntityLocationPairs(alignmentToHunt, encounterResponse.Encounter.Board.Entities.Entities)
+	trace.rejectUnaffordableCards(encounter.ActiveEntity)
 
-	theBestPlay := GetPlayThatDealsTheMostDamageToTheLowestHealthTargets(bestMovesInDamageOrder, entityLocationVertexPairs)
+	if options != nil && options.ForgetCards > 0 && encounter.ActiveEntity.Hand != nil {
+		// Plan against a copy so that the caller's hand is left alone.
+		encounter = proto.Clone(encounter).(*deviant.Encounter)
+
+		remembered, forgotten := options.forgetCards(encounter.ActiveEntity.Hand.Cards)
+		encounter.ActiveEntity.Hand.Cards = remembered
+		trace.forgetCards(forgotten)
+	}
+
+	var match *sim.Match
 
-	if theBestPlay != nil {
-		moveEncounterRequest := GenerateMoveAction(theBestPlay, encounterResponse.Encounter)
-		targetEncounterRequest := GenerateTargetAction(theBestPlay, encounterResponse.Encounter)
-		playEncounterRequest := GeneratePlayAction(theBestPlay, encounterResponse.Encounter)
-		clearTargetAction := GenerateClearTargetAction(encounterResponse.Encounter)
+	for step := 0; step < options.depth(); step++ {
+		stepTrace := trace
+		if step > 0 {
+			stepTrace = newTrace(encounter, alignmentToHunt)
+		}
+
+		stepRequests, played := takeStep(encounter, alignmentToHunt, stepTrace, options)
+
+		// Moving twice in one turn never gets any closer, so later steps only ever play cards.
+		if step > 0 && !played {
+			break
+		}
 
-		encounterRequests = append(encounterRequests, moveEncounterRequest)
-		encounterRequests = append(encounterRequests, targetEncounterRequest)
-		encounterRequests = append(encounterRequests, playEncounterRequest)
-		encounterRequests = append(encounterRequests, clearTargetAction)
-	} else {
-		moveEncounterRequest := GenerateClosestMove(alignmentToHunt, encounterResponse.Encounter)
-		encounterRequests = append(encounterRequests, moveEncounterRequest)
+		encounterRequests = append(encounterRequests, stepRequests...)
+		if step > 0 {
+			trace.followUp(stepTrace.Choice)
+		}
+
+		if step+1 == options.depth() {
+			break
+		}
+
+		if match == nil {
+			var err error
+			if match, err = sim.New(encounter); err != nil {
+				break
+			}
+		}
+
+		applied := true
+		for _, stepRequest := range stepRequests {
+			if err := match.Apply(stepRequest); err != nil {
+				applied = false
+				break
+			}
+		}
+
+		if !applied || match.Completed() {
+			break
+		}
+
+		encounter = match.Response(encounterResponse.PlayerId).Encounter
 	}
 
 	endTurnEncounterRequest := GenerateEndTurnAction(encounterResponse.Encounter)
@@ -737,3 +827,53 @@ func TakeTurn(encounterResponse *deviant.EncounterResponse, alignmentToHunt devi
 
 	return encounterRequests
 }
+
+// takeStep plans a single card play, or a move towards the hunted alignment when nothing is in reach, and reports
+// whether a card is played.
+func takeStep(encounter *deviant.Encounter, alignmentToHunt deviant.Alignment, trace *Trace, options *Options) ([]*deviant.EncounterRequest, bool) {
+	encounterRequests := []*deviant.EncounterRequest{}
+
+	allHittingMoveCombinations := FilterCardPlaysToHits(encounter.Board.Entities.Entities, encounter, alignmentToHunt)
+	bestMovesInDamageOrder := SortCardPlaysByDamageInflicted(alignmentToHunt, allHittingMoveCombinations, encounter.Board.Entities)
+	entityLocationVertexPairs := GenerateEntityLocationPairs(alignmentToHunt, encounter.Board.Entities.Entities)
+	candidates := trace.addCandidates(bestMovesInDamageOrder, encounter.Board.Entities)
+
+	theBestPlay := GetPlayThatDealsTheMostDamageToTheLowestHealthTargets(bestMovesInDamageOrder, entityLocationVertexPairs)
+
+	if theBestPlay == nil {
+		// With nothing left to hunt there is nowhere to move towards, so the turn just ends.
+		if moveEncounterRequest := generateClosestMove(alignmentToHunt, encounter, trace); moveEncounterRequest != nil {
+			encounterRequests = append(encounterRequests, moveEncounterRequest)
+		}
+
+		return encounterRequests, false
+	}
+
+	greedy := 0
+	for i, play := range bestMovesInDamageOrder {
+		if play == theBestPlay {
+			greedy = i
+			break
+		}
+	}
+
+	if options.ranksPlays() {
+		greedy = bestRanked(encounter, alignmentToHunt, bestMovesInDamageOrder, greedy)
+	}
+
+	chosen, method := options.choose(bestMovesInDamageOrder, greedy)
+	trace.choosePlay(candidates, chosen, method)
+	theChosenPlay := bestMovesInDamageOrder[chosen]
+
+	moveEncounterRequest := GenerateMoveAction(theChosenPlay, encounter)
+	targetEncounterRequest := GenerateTargetAction(theChosenPlay, encounter)
+	playEncounterRequest := GeneratePlayAction(theChosenPlay, encounter)
+	clearTargetAction := GenerateClearTargetAction(encounter)
+
+	encounterRequests = append(encounterRequests, moveEncounterRequest)
+	encounterRequests = append(encounterRequests, targetEncounterRequest)
+	encounterRequests = append(encounterRequests, playEncounterRequest)
+	encounterRequests = append(encounterRequests, clearTargetAction)
+
+	return encounterRequests, true
+}
@@ -1,11 +1,16 @@
 package hunting
 
 import (
+	"fmt"
 	"math/rand"
+	"runtime/debug"
 	"testing"
-	"time"
+	"testing/quick"
 
 	"github.com/google/uuid"
+	"github.com/recluse-games/deviant-glados/encgen"
+	"github.com/recluse-games/deviant-glados/sim"
+	"google.golang.org/protobuf/proto"
 
 	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
 )
@@ -441,10 +446,10 @@ func generateCardLiterals(size int32, class deviant.Classes) []*deviant.Card {
 		}
 	}
 
-	// Suffle the Deck
-	rand.Seed(time.Now().UnixNano())
+	// Suffle the Deck, with a fixed seed so that every run plays the same cards
+	shuffle := rand.New(rand.NewSource(int64(size)))
 	for i := len(cardLiterals) - 1; i > 0; i-- { // Fisher–Yates shuffle
-		j := rand.Intn(i + 1)
+		j := shuffle.Intn(i + 1)
 		cardLiterals[i], cardLiterals[j] = cardLiterals[j], cardLiterals[i]
 	}
 
@@ -872,6 +877,75 @@ func generateMatch() *deviant.Encounter {
 	return test
 }
 
+func TestGeneratePermissableMovesStaysWithinAp(t *testing.T) {
+	rows := []*deviant.EntitiesRow{}
+	for x := 0; x < 9; x++ {
+		row := &deviant.EntitiesRow{}
+		for y := 0; y < 8; y++ {
+			row.Entities = append(row.Entities, &deviant.Entity{})
+		}
+		rows = append(rows, row)
+	}
+
+	rows[4].Entities[3] = &deviant.Entity{Id: "0001"}
+	entities := &deviant.Entities{Entities: rows}
+
+	// Tiles one step past the AP limit used to be filled, offering moves the server refuses. Staying put is free.
+	for ap, want := range map[int32]int{0: 1, 1: 5, 2: 13} {
+		tiles := GeneratePermissableMoves(&gridNode{X: 4, Y: 3}, ap, entities)
+		if len(tiles) != want {
+			t.Errorf("%d ap: expected %d tiles, got %d", ap, want, len(tiles))
+		}
+
+		for _, tile := range tiles {
+			if int32(tile.apCost) > ap {
+				t.Errorf("%d ap: (%d, %d) costs %d ap", ap, tile.X, tile.Y, tile.apCost)
+			}
+		}
+	}
+}
+
+func TestGeneratePermissableMovesFitsTheBoard(t *testing.T) {
+	board := func(width int, height int) *deviant.Entities {
+		rows := []*deviant.EntitiesRow{}
+		for x := 0; x < width; x++ {
+			row := &deviant.EntitiesRow{}
+			for y := 0; y < height; y++ {
+				row.Entities = append(row.Entities, &deviant.Entity{})
+			}
+			rows = append(rows, row)
+		}
+
+		return &deviant.Entities{Entities: rows}
+	}
+
+	// The fill used to stop at a fixed 9x8 board, panicking on smaller boards and never reaching the edge of larger
+	// ones.
+	tests := map[string]struct {
+		width, height int
+		origin        *gridNode
+		want          int
+	}{
+		"smaller": {5, 5, &gridNode{X: 2, Y: 2}, 25},
+		"larger":  {12, 10, &gridNode{X: 7, Y: 5}, 41},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tiles := GeneratePermissableMoves(test.origin, 4, board(test.width, test.height))
+			if len(tiles) != test.want {
+				t.Fatalf("expected %d tiles, got %d", test.want, len(tiles))
+			}
+
+			for _, tile := range tiles {
+				if int(tile.X) >= test.width || int(tile.Y) >= test.height {
+					t.Errorf("(%d, %d) lies outside the %dx%d board", tile.X, tile.Y, test.width, test.height)
+				}
+			}
+		})
+	}
+}
+
 func TestGenerateCardVertexPairs(t *testing.T) {
 	match := generateMatch()
 	startingVertex := &gridNode{
@@ -917,7 +991,7 @@ func TestSortCardPlaysByDamageInflicted(t *testing.T) {
 	match := generateMatch()
 
 	allHittingMoveCombinations := FilterCardPlaysToHits(match.Board.Entities.Entities, match, deviant.Alignment_NEUTRAL)
-	bestMovesInDamageOrder := SortCardPlaysByDamageInflicted(allHittingMoveCombinations, match.Board.Entities)
+	bestMovesInDamageOrder := SortCardPlaysByDamageInflicted(deviant.Alignment_NEUTRAL, allHittingMoveCombinations, match.Board.Entities)
 
 	for _, vertex := range bestMovesInDamageOrder {
 		t.Log(vertex.cardVertexPair.card.Id)
@@ -931,7 +1005,7 @@ func TestGetPlayThatDealsTheMostDamageToTheLowestHealthTargets(t *testing.T) {
 	match := generateMatch()
 
 	allHittingMoveCombinations := FilterCardPlaysToHits(match.Board.Entities.Entities, match, deviant.Alignment_NEUTRAL)
-	bestMovesInDamageOrder := SortCardPlaysByDamageInflicted(allHittingMoveCombinations, match.Board.Entities)
+	bestMovesInDamageOrder := SortCardPlaysByDamageInflicted(deviant.Alignment_NEUTRAL, allHittingMoveCombinations, match.Board.Entities)
 	entityLocationVertexPairs := GenerateEntityLocationPairs(deviant.Alignment_NEUTRAL, match.Board.Entities.Entities)
 	theBestPlay := GetPlayThatDealsTheMostDamageToTheLowestHealthTargets(bestMovesInDamageOrder, entityLocationVertexPairs)
 
@@ -940,3 +1014,263 @@ func TestGetPlayThatDealsTheMostDamageToTheLowestHealthTargets(t *testing.T) {
 	t.Log(theBestPlay.origin)
 	t.Log(theBestPlay.rotation)
 }
+
+func BenchmarkTakeTurn(b *testing.B) {
+	encounters := []*deviant.EncounterResponse{}
+	for seed := int64(0); seed < 16; seed++ {
+		config := encgen.DefaultConfig()
+		config.Seed = seed
+
+		encounter, err := encgen.Generate(config)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		encounters = append(encounters, &deviant.EncounterResponse{Encounter: encounter})
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		encounterResponse := encounters[i%len(encounters)]
+		TakeTurn(encounterResponse, opposing(encounterResponse.Encounter.ActiveEntity.Alignment))
+	}
+}
+
+// randomEncounter generates an encounter of any shape encgen supports, with the active entity part way through its
+// turn.
+func randomEncounter(seed int64) (*deviant.Encounter, error) {
+	random := rand.New(rand.NewSource(seed))
+
+	config := encgen.DefaultConfig()
+	config.Seed = seed
+	config.Rows = 2 + random.Intn(11)
+	config.Columns = 1 + random.Intn(12)
+	config.Friendly = 1 + random.Intn(config.Columns)
+	config.Unfriendly = 1 + random.Intn(config.Columns)
+	config.Mirror = config.Friendly == config.Unfriendly && random.Intn(2) == 0
+	config.HP = encgen.Range{Min: 1, Max: 1 + random.Int31n(10)}
+	config.AP = encgen.Range{Min: 1, Max: 1 + random.Int31n(8)}
+	config.DeckSize = random.Intn(7)
+	config.HandSize = random.Intn(config.DeckSize + 1)
+	config.Obstacles = random.Float64() / 2
+
+	classes := config.Classes
+	random.Shuffle(len(classes), func(i, j int) { classes[i], classes[j] = classes[j], classes[i] })
+	config.Classes = classes[:1+random.Intn(len(classes))]
+
+	encounter, err := encgen.Generate(config)
+	if err != nil {
+		return nil, fmt.Errorf("%+v: %v", config, err)
+	}
+
+	encounter.ActiveEntity.Ap = random.Int31n(encounter.ActiveEntity.MaxAp + 1)
+
+	// Some boards have nothing left to hunt, as once the last opponent falls mid-match.
+	if random.Intn(8) == 0 {
+		removeAlignment(encounter, opposing(encounter.ActiveEntity.Alignment))
+	}
+
+	return encounter, nil
+}
+
+// removeAlignment clears every entity of alignment from the board and the turn order.
+func removeAlignment(encounter *deviant.Encounter, alignment deviant.Alignment) {
+	removed := map[string]bool{}
+
+	for _, row := range encounter.Board.Entities.Entities {
+		for y, entity := range row.Entities {
+			if entity.Id != "" && entity.Alignment == alignment {
+				removed[entity.Id] = true
+				row.Entities[y] = &deviant.Entity{}
+			}
+		}
+	}
+
+	order := encounter.ActiveEntityOrder[:0]
+	for _, id := range encounter.ActiveEntityOrder {
+		if !removed[id] {
+			order = append(order, id)
+		}
+	}
+
+	encounter.ActiveEntityOrder = order
+}
+
+// checkProperty checks that property holds for many random encounters, reporting the seed of any that break it.
+func checkProperty(t *testing.T, property func(encounter *deviant.Encounter) error) {
+	t.Helper()
+
+	count := 500
+	if testing.Short() {
+		count = 50
+	}
+
+	holds := func(seed int64) (ok bool) {
+		encounter, err := randomEncounter(seed)
+		if err != nil {
+			t.Errorf("seed %d: %v", seed, err)
+			return false
+		}
+
+		defer func() {
+			if r := recover(); r != nil {
+				t.Errorf("seed %d: panic: %v\n%s", seed, r, debug.Stack())
+				ok = false
+			}
+		}()
+
+		if err := property(encounter); err != nil {
+			t.Errorf("seed %d: %v", seed, err)
+			return false
+		}
+
+		return true
+	}
+
+	if err := quick.Check(holds, &quick.Config{MaxCount: count, Rand: rand.New(rand.NewSource(1))}); err != nil {
+		t.Error(err)
+	}
+}
+
+func manhattan(a *Vertex, b *gridNode) int {
+	distance := 0
+	for _, d := range []int{a.X - int(b.X), a.Y - int(b.Y)} {
+		if d < 0 {
+			d = -d
+		}
+		distance += d
+	}
+
+	return distance
+}
+
+func TestGeneratePermissableMovesProperties(t *testing.T) {
+	checkProperty(t, func(encounter *deviant.Encounter) error {
+		active := encounter.ActiveEntity
+		rows := encounter.Board.Entities.Entities
+		origin := GetEntityVertex(active, rows)
+
+		for _, tile := range GenerateValidMoveVertexes(active, rows, encounter) {
+			if tile.X < 0 || int(tile.X) >= len(rows) || tile.Y < 0 || int(tile.Y) >= len(rows[tile.X].Entities) {
+				return fmt.Errorf("(%d, %d) is off the board", tile.X, tile.Y)
+			}
+
+			if occupant := rows[tile.X].Entities[tile.Y]; occupant.Id != "" && occupant.Id != active.Id {
+				return fmt.Errorf("(%d, %d) is occupied by %s", tile.X, tile.Y, occupant.Id)
+			}
+
+			if tile.apCost != manhattan(origin, tile) || int32(tile.apCost) > active.Ap {
+				return fmt.Errorf("(%d, %d) costs %d ap with %d ap available", tile.X, tile.Y, tile.apCost, active.Ap)
+			}
+
+			// The move must be one the server's rules accept.
+			match, err := sim.New(encounter)
+			if err != nil {
+				return err
+			}
+
+			// A board with one side left is already won, so the server accepts no moves at all on it.
+			if err := match.Apply(GenerateMoveAction(&CardVertexRotationPair{origin: &Vertex{X: int(tile.X), Y: int(tile.Y)}}, encounter)); err != nil && err != sim.ErrCompleted {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func TestGenerateCardVertexPairsProperties(t *testing.T) {
+	rotations := []deviant.EntityRotationNames{
+		deviant.EntityRotationNames_NORTH, deviant.EntityRotationNames_SOUTH, deviant.EntityRotationNames_EAST, deviant.EntityRotationNames_WEST,
+	}
+
+	checkProperty(t, func(encounter *deviant.Encounter) error {
+		active := encounter.ActiveEntity
+		rows := encounter.Board.Entities.Entities
+
+		for _, tile := range GenerateValidMoveVertexes(active, rows, encounter) {
+			// sizes counts the tiles each card hits in each rotation, and distances sums their distance from the tile.
+			sizes := map[string]map[deviant.EntityRotationNames]int{}
+			distances := map[string]map[deviant.EntityRotationNames]int{}
+
+			for _, rotation := range rotations {
+				for _, pair := range GenerateCardVertexPairs(tile, active, rows, rotation) {
+					card := pair.cardVertexPair.card
+					if card.Cost > active.Ap-int32(tile.apCost) {
+						return fmt.Errorf("%s costs %d ap with %d ap left after moving", card.InstanceId, card.Cost, active.Ap-int32(tile.apCost))
+					}
+
+					if pair.rotation != rotation {
+						return fmt.Errorf("%s was rotated %s rather than %s", card.InstanceId, pair.rotation, rotation)
+					}
+
+					if sizes[card.InstanceId] == nil {
+						sizes[card.InstanceId] = map[deviant.EntityRotationNames]int{}
+						distances[card.InstanceId] = map[deviant.EntityRotationNames]int{}
+					}
+
+					sizes[card.InstanceId][rotation]++
+					distances[card.InstanceId][rotation] += manhattan(pair.cardVertexPair.vertex, tile)
+				}
+			}
+
+			for _, card := range active.Hand.Cards {
+				size := 0
+				for _, pattern := range card.Action.Pattern {
+					size += int(pattern.Distance)
+				}
+
+				if card.Cost > active.Ap-int32(tile.apCost) {
+					size = 0
+				}
+
+				for _, rotation := range rotations {
+					if sizes[card.InstanceId][rotation] != size {
+						return fmt.Errorf("%s hits %d tiles facing %s rather than %d", card.InstanceId, sizes[card.InstanceId][rotation], rotation, size)
+					}
+
+					if distances[card.InstanceId][rotation] != distances[card.InstanceId][deviant.EntityRotationNames_SOUTH] {
+						return fmt.Errorf("%s changes shape facing %s", card.InstanceId, rotation)
+					}
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+func TestTakeTurnProperties(t *testing.T) {
+	checkProperty(t, func(encounter *deviant.Encounter) error {
+		alignment := opposing(encounter.ActiveEntity.Alignment)
+
+		plans := map[string][]*deviant.EncounterRequest{
+			"default": TakeTurn(&deviant.EncounterResponse{Encounter: proto.Clone(encounter).(*deviant.Encounter)}, alignment),
+		}
+
+		for _, difficulty := range Difficulties {
+			options := difficulty.Options()
+			plans[string(difficulty)], _ = TakeTurnWithOptions(&deviant.EncounterResponse{Encounter: proto.Clone(encounter).(*deviant.Encounter)}, alignment, &options)
+		}
+
+		for name, requests := range plans {
+			if len(requests) == 0 {
+				return fmt.Errorf("%s: planned no requests", name)
+			}
+
+			match, err := sim.New(encounter)
+			if err != nil {
+				return err
+			}
+
+			// Every move must be reachable and every play affordable with the ap left.
+			for _, request := range requests {
+				if err := match.Apply(request); err != nil && err != sim.ErrCompleted {
+					return fmt.Errorf("%s: %v", name, err)
+				}
+			}
+		}
+
+		return nil
+	})
+}
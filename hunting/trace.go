@@ -0,0 +1,334 @@
+package hunting
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// TracePoint a board location as recorded in a trace.
+type TracePoint struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// TraceCandidate a single scored card play considered while planning a turn.
+type TraceCandidate struct {
+	CardID         string     `json:"cardId"`
+	CardInstanceID string     `json:"cardInstanceId"`
+	Origin         TracePoint `json:"origin"`
+	MoveCost       int        `json:"moveCost"`
+	Rotation       string     `json:"rotation"`
+	Target         TracePoint `json:"target"`
+	TargetID       string     `json:"targetId,omitempty"`
+	TargetHp       int32      `json:"targetHp"`
+	Score          int        `json:"score"`
+}
+
+// TraceRejection an option that was considered and discarded, along with why.
+type TraceRejection struct {
+	Candidate *TraceCandidate `json:"candidate,omitempty"`
+	CardID    string          `json:"cardId,omitempty"`
+	Reason    string          `json:"reason"`
+}
+
+// TraceChoice the option TakeTurn settled on.
+type TraceChoice struct {
+	Kind string `json:"kind"`
+	// Method is how a card play was chosen among the candidates, such as greedy or softmax.
+	Method    string          `json:"method,omitempty"`
+	Candidate *TraceCandidate `json:"candidate,omitempty"`
+	Move      *TracePoint     `json:"move,omitempty"`
+	Distance  int             `json:"distance,omitempty"`
+}
+
+// Choice kinds recorded in a trace.
+const (
+	ChoicePlayCard    = "play_card"
+	ChoiceMoveClosest = "move_closest"
+)
+
+// Trace a structured explanation of a single TakeTurn decision.
+//
+// A trace carries the encounter and options it was produced from so that it can be attached to a bug report and
+// replayed with TakeTurnWithOptions.
+type Trace struct {
+	EncounterID     string
+	TurnID          string
+	ActiveEntityID  string
+	AlignmentToHunt deviant.Alignment
+	// Options are the options the turn was planned with, including its seed, or nil when it was planned without.
+	Options    *Options
+	Encounter  *deviant.Encounter
+	Candidates []*TraceCandidate
+	Rejected   []*TraceRejection
+	Choice     *TraceChoice
+	// FollowUps are the choices made for the steps after the first when planning more than one step a turn.
+	FollowUps []*TraceChoice
+	Requests  []*deviant.EncounterRequest
+}
+
+type traceJSON struct {
+	EncounterID     string            `json:"encounterId"`
+	TurnID          string            `json:"turnId"`
+	ActiveEntityID  string            `json:"activeEntityId"`
+	AlignmentToHunt string            `json:"alignmentToHunt"`
+	Options         *Options          `json:"options,omitempty"`
+	Encounter       json.RawMessage   `json:"encounter,omitempty"`
+	Candidates      []*TraceCandidate `json:"candidates"`
+	Rejected        []*TraceRejection `json:"rejected"`
+	Choice          *TraceChoice      `json:"choice"`
+	FollowUps       []*TraceChoice    `json:"followUps,omitempty"`
+	Requests        []json.RawMessage `json:"requests"`
+}
+
+func newTrace(encounter *deviant.Encounter, alignmentToHunt deviant.Alignment) *Trace {
+	trace := &Trace{
+		EncounterID:     encounter.Id,
+		AlignmentToHunt: alignmentToHunt,
+		Encounter:       encounter,
+		Candidates:      []*TraceCandidate{},
+		Rejected:        []*TraceRejection{},
+	}
+
+	if encounter.Turn != nil {
+		trace.TurnID = encounter.Turn.Id
+	}
+
+	if encounter.ActiveEntity != nil {
+		trace.ActiveEntityID = encounter.ActiveEntity.Id
+	}
+
+	return trace
+}
+
+func newTraceCandidate(cardVertexRotationPair *CardVertexRotationPair, entities *deviant.Entities) *TraceCandidate {
+	candidate := &TraceCandidate{
+		CardID:         cardVertexRotationPair.cardVertexPair.card.Id,
+		CardInstanceID: cardVertexRotationPair.cardVertexPair.card.InstanceId,
+		Origin: TracePoint{
+			X: cardVertexRotationPair.origin.X,
+			Y: cardVertexRotationPair.origin.Y,
+		},
+		MoveCost: cardVertexRotationPair.origin.apCost,
+		Rotation: cardVertexRotationPair.rotation.String(),
+		Target: TracePoint{
+			X: cardVertexRotationPair.cardVertexPair.vertex.X,
+			Y: cardVertexRotationPair.cardVertexPair.vertex.Y,
+		},
+		Score: cardVertexRotationPair.damage,
+	}
+
+	target := entities.Entities[candidate.Target.X].Entities[candidate.Target.Y]
+	candidate.TargetID = target.Id
+	candidate.TargetHp = target.Hp
+
+	return candidate
+}
+
+func (t *Trace) addCandidates(cardVertexRotationPairs []*CardVertexRotationPair, entities *deviant.Entities) []*TraceCandidate {
+	if t == nil {
+		return nil
+	}
+
+	candidates := []*TraceCandidate{}
+	for _, cardVertexRotationPair := range cardVertexRotationPairs {
+		candidates = append(candidates, newTraceCandidate(cardVertexRotationPair, entities))
+	}
+
+	t.Candidates = append(t.Candidates, candidates...)
+
+	return candidates
+}
+
+func (t *Trace) rejectUnaffordableCards(entity *deviant.Entity) {
+	if t == nil || entity.Hand == nil {
+		return
+	}
+
+	for _, card := range entity.Hand.Cards {
+		if card.Cost > entity.Ap {
+			t.Rejected = append(t.Rejected, &TraceRejection{
+				CardID: card.Id,
+				Reason: fmt.Sprintf("costs %d ap but only %d ap is available", card.Cost, entity.Ap),
+			})
+		}
+	}
+}
+
+func (t *Trace) forgetCards(cards []*deviant.Card) {
+	if t == nil {
+		return
+	}
+
+	for _, card := range cards {
+		t.Rejected = append(t.Rejected, &TraceRejection{
+			CardID: card.Id,
+			Reason: "forgotten",
+		})
+	}
+}
+
+func (t *Trace) followUp(choice *TraceChoice) {
+	if t == nil || choice == nil {
+		return
+	}
+
+	t.FollowUps = append(t.FollowUps, choice)
+}
+
+func (t *Trace) choosePlay(candidates []*TraceCandidate, chosen int, method string) {
+	if t == nil {
+		return
+	}
+
+	choice := candidates[chosen]
+	t.Choice = &TraceChoice{
+		Kind:      ChoicePlayCard,
+		Method:    method,
+		Candidate: choice,
+	}
+
+	for i, candidate := range candidates {
+		if i == chosen {
+			continue
+		}
+
+		var reason string
+
+		switch {
+		case candidate.TargetID != choice.TargetID:
+			reason = fmt.Sprintf("targets %s with %d hp; %s has %d hp", candidate.TargetID, candidate.TargetHp, choice.TargetID, choice.TargetHp)
+		case candidate.Score < choice.Score:
+			reason = fmt.Sprintf("scores %d; chosen play scores %d", candidate.Score, choice.Score)
+		case candidate.Score > choice.Score:
+			reason = fmt.Sprintf("scores %d; %s choice scores %d", candidate.Score, method, choice.Score)
+		default:
+			reason = fmt.Sprintf("ties chosen play at %d; earlier candidate preferred", candidate.Score)
+		}
+
+		t.Rejected = append(t.Rejected, &TraceRejection{
+			Candidate: candidate,
+			Reason:    reason,
+		})
+	}
+}
+
+func (t *Trace) chooseMove(x int32, y int32, distance int) {
+	if t == nil {
+		return
+	}
+
+	t.Choice = &TraceChoice{
+		Kind: ChoiceMoveClosest,
+		Move: &TracePoint{
+			X: int(x),
+			Y: int(y),
+		},
+		Distance: distance,
+	}
+}
+
+// MarshalJSON encodes the trace, using the canonical protobuf JSON mapping for the encounter and requests.
+func (t *Trace) MarshalJSON() ([]byte, error) {
+	out := &traceJSON{
+		EncounterID:     t.EncounterID,
+		TurnID:          t.TurnID,
+		ActiveEntityID:  t.ActiveEntityID,
+		AlignmentToHunt: t.AlignmentToHunt.String(),
+		Options:         t.Options,
+		Candidates:      t.Candidates,
+		Rejected:        t.Rejected,
+		Choice:          t.Choice,
+		FollowUps:       t.FollowUps,
+		Requests:        []json.RawMessage{},
+	}
+
+	if t.Encounter != nil {
+		encounter, err := protojson.Marshal(t.Encounter)
+		if err != nil {
+			return nil, err
+		}
+
+		out.Encounter = encounter
+	}
+
+	for _, request := range t.Requests {
+		encodedRequest, err := protojson.Marshal(request)
+		if err != nil {
+			return nil, err
+		}
+
+		out.Requests = append(out.Requests, encodedRequest)
+	}
+
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON decodes a trace previously produced by MarshalJSON.
+func (t *Trace) UnmarshalJSON(data []byte) error {
+	in := &traceJSON{}
+	if err := json.Unmarshal(data, in); err != nil {
+		return err
+	}
+
+	alignment, ok := deviant.Alignment_value[in.AlignmentToHunt]
+	if !ok {
+		return fmt.Errorf("unknown alignment %q", in.AlignmentToHunt)
+	}
+
+	*t = Trace{
+		EncounterID:     in.EncounterID,
+		TurnID:          in.TurnID,
+		ActiveEntityID:  in.ActiveEntityID,
+		AlignmentToHunt: deviant.Alignment(alignment),
+		Options:         in.Options,
+		Candidates:      in.Candidates,
+		Rejected:        in.Rejected,
+		Choice:          in.Choice,
+		FollowUps:       in.FollowUps,
+	}
+
+	if len(in.Encounter) > 0 {
+		t.Encounter = &deviant.Encounter{}
+		if err := protojson.Unmarshal(in.Encounter, t.Encounter); err != nil {
+			return err
+		}
+	}
+
+	for _, encodedRequest := range in.Requests {
+		request := &deviant.EncounterRequest{}
+		if err := protojson.Unmarshal(encodedRequest, request); err != nil {
+			return err
+		}
+
+		t.Requests = append(t.Requests, request)
+	}
+
+	return nil
+}
+
+// WriteJSON writes the trace to w as indented JSON.
+func (t *Trace) WriteJSON(w io.Writer) error {
+	encoded, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(append(encoded, '\n'))
+
+	return err
+}
+
+// ReadTrace reads a JSON trace written by WriteJSON.
+func ReadTrace(r io.Reader) (*Trace, error) {
+	trace := &Trace{}
+	if err := json.NewDecoder(r).Decode(trace); err != nil {
+		return nil, err
+	}
+
+	return trace, nil
+}
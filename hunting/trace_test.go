@@ -0,0 +1,128 @@
+package hunting
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/recluse-games/deviant-glados/render"
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+)
+
+// renderTurn draws the encounter with the active entity's reachable tiles and the planned turn for failure messages.
+func renderTurn(encounter *deviant.Encounter, requests []*deviant.EncounterRequest) string {
+	options := render.PlannedTurn(encounter, requests)
+
+	for _, tile := range GenerateValidMoveVertexes(encounter.ActiveEntity, encounter.Board.Entities.Entities, encounter) {
+		options.Reachable = append(options.Reachable, render.Point{X: int(tile.X), Y: int(tile.Y)})
+	}
+
+	return render.Board(encounter, options)
+}
+
+func TestTakeTurnWithTrace(t *testing.T) {
+	match := generateMatch()
+
+	requests, trace := TakeTurnWithTrace(&deviant.EncounterResponse{Encounter: match}, deviant.Alignment_NEUTRAL)
+
+	if trace.EncounterID != match.Id || trace.ActiveEntityID != match.ActiveEntity.Id {
+		t.Fatalf("trace does not identify the encounter: %v %v", trace.EncounterID, trace.ActiveEntityID)
+	}
+
+	if len(trace.Candidates) == 0 {
+		t.Fatal("expected candidates to be recorded")
+	}
+
+	if trace.Choice == nil || trace.Choice.Kind != ChoicePlayCard {
+		t.Fatalf("expected a card play to be chosen, got %v\n%s", trace.Choice, renderTurn(match, requests))
+	}
+
+	if len(trace.Rejected) != len(trace.Candidates)-1 {
+		t.Errorf("expected every other candidate to be rejected, got %v of %v", len(trace.Rejected), len(trace.Candidates))
+	}
+
+	for _, rejection := range trace.Rejected {
+		if rejection.Reason == "" {
+			t.Errorf("rejection without a reason: %v", rejection.Candidate)
+		}
+	}
+
+	if len(trace.Requests) != len(requests) {
+		t.Errorf("expected %v traced requests, got %v", len(requests), len(trace.Requests))
+	}
+}
+
+func TestTakeTurnWithTraceMovesWhenNothingIsInRange(t *testing.T) {
+	match := generateMatch()
+
+	requests, trace := TakeTurnWithTrace(&deviant.EncounterResponse{Encounter: match}, deviant.Alignment_UNFRIENDLY)
+
+	if trace.Choice == nil || trace.Choice.Kind != ChoiceMoveClosest || trace.Choice.Move == nil {
+		t.Fatalf("expected a closest move to be chosen, got %v\n%s", trace.Choice, renderTurn(match, requests))
+	}
+}
+
+func TestTraceJSONRoundTrip(t *testing.T) {
+	match := generateMatch()
+
+	requests, trace := TakeTurnWithTrace(&deviant.EncounterResponse{Encounter: match}, deviant.Alignment_NEUTRAL)
+
+	buffer := &bytes.Buffer{}
+	if err := trace.WriteJSON(buffer); err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := ReadTrace(buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded.AlignmentToHunt != trace.AlignmentToHunt || len(decoded.Candidates) != len(trace.Candidates) {
+		t.Fatalf("decoded trace does not match: %v", decoded)
+	}
+
+	// Replaying the decoded encounter must produce the same decision.
+	replayed, _ := TakeTurnWithTrace(&deviant.EncounterResponse{Encounter: decoded.Encounter}, decoded.AlignmentToHunt)
+	if len(replayed) != len(requests) {
+		t.Fatalf("expected %v replayed requests, got %v", len(requests), len(replayed))
+	}
+
+	for i := range replayed {
+		if replayed[i].String() != decoded.Requests[i].String() {
+			t.Errorf("request %v differs on replay: %v != %v", i, replayed[i], decoded.Requests[i])
+		}
+	}
+}
+
+func TestTraceJSONReplaysOptions(t *testing.T) {
+	encounter := generateSkirmish()
+	place(encounter, 1, 0, &deviant.Entity{Id: "0002", Name: "Ben", Hp: 4, Alignment: deviant.Alignment_UNFRIENDLY})
+
+	options := Easy.Options()
+	options.Seed = 39
+	requests, trace := TakeTurnWithOptions(&deviant.EncounterResponse{Encounter: encounter}, deviant.Alignment_UNFRIENDLY, &options)
+
+	buffer := &bytes.Buffer{}
+	if err := trace.WriteJSON(buffer); err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := ReadTrace(buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded.Options == nil || *decoded.Options != options {
+		t.Fatalf("expected the options to be decoded but got %+v", decoded.Options)
+	}
+
+	replayed, _ := TakeTurnWithOptions(&deviant.EncounterResponse{Encounter: decoded.Encounter}, decoded.AlignmentToHunt, decoded.Options)
+	if len(replayed) != len(requests) {
+		t.Fatalf("expected %v replayed requests, got %v", len(requests), len(replayed))
+	}
+
+	for i := range replayed {
+		if replayed[i].String() != requests[i].String() {
+			t.Errorf("request %v differs on replay: %v != %v", i, replayed[i], requests[i])
+		}
+	}
+}
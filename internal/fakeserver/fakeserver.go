@@ -0,0 +1,313 @@
+// Package fakeserver serves the encounter service in process on top of the rules simulator, so that tests can play
+// whole matches between bots without a real server.
+//
+// Like the real server, a stream must start by registering its player, and the match starts once every player owning
+// an entity has registered. Every accepted request is broadcast to all players as the new state of the encounter,
+// while a rejected request only sends the unchanged state back to its sender. A player registering again, such as
+// after reconnecting, replaces its earlier stream and is sent the current state.
+package fakeserver
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/recluse-games/deviant-glados/sim"
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+// bufferSize the size of the in-memory connection buffer, large enough for a whole encounter.
+const bufferSize = 1 << 20
+
+// Event a request received by the server.
+type Event struct {
+	PlayerID string
+	Request  *deviant.EncounterRequest
+	// TurnID and ActiveEntityID describe the encounter when the request arrived.
+	TurnID         string
+	ActiveEntityID string
+	// Err is why the request was rejected, nil when it was applied.
+	Err error
+}
+
+// Server an in-process encounter server playing a single match.
+type Server struct {
+	deviant.UnimplementedEncounterServiceServer
+
+	// MaxTurns ends the match as a draw once this many turns have been played, never when zero.
+	MaxTurns int
+
+	mu            sync.Mutex
+	match         *sim.Match
+	players       map[string]bool
+	streams       map[string]deviant.EncounterService_UpdateEncounterServer
+	registrations []string
+	events        []Event
+	started       bool
+	done          chan struct{}
+
+	listener *Listener
+}
+
+// New creates a server playing encounter, waiting for the owner of every entity on the board to register.
+func New(encounter *deviant.Encounter) (*Server, error) {
+	match, err := sim.New(encounter)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		match:   match,
+		players: map[string]bool{},
+		streams: map[string]deviant.EncounterService_UpdateEncounterServer{},
+		done:    make(chan struct{}),
+	}
+
+	for _, row := range encounter.Board.Entities.Entities {
+		for _, entity := range row.Entities {
+			if entity.Id != "" && entity.OwnerId != "" {
+				s.players[entity.OwnerId] = true
+			}
+		}
+	}
+
+	return s, nil
+}
+
+// Listener serves gRPC services on an in-memory listener. Tests needing no more than a scripted encounter server can
+// serve it with Serve rather than playing a match, and tests of other services can serve them with Listen.
+type Listener struct {
+	listener   *bufconn.Listener
+	grpcServer *grpc.Server
+}
+
+// Listen serves whatever register adds to a gRPC server on an in-memory listener until Stop is called.
+func Listen(register func(*grpc.Server)) *Listener {
+	l := &Listener{
+		listener:   bufconn.Listen(bufferSize),
+		grpcServer: grpc.NewServer(),
+	}
+
+	register(l.grpcServer)
+
+	go l.grpcServer.Serve(l.listener)
+
+	return l
+}
+
+// Serve serves server on an in-memory listener until Stop is called.
+func Serve(server deviant.EncounterServiceServer) *Listener {
+	return Listen(func(grpcServer *grpc.Server) {
+		deviant.RegisterEncounterServiceServer(grpcServer, server)
+	})
+}
+
+// Stop closes every stream and the listener.
+func (l *Listener) Stop() {
+	l.grpcServer.Stop()
+}
+
+// DialOption connects a client to the listener whatever address it dials.
+func (l *Listener) DialOption() grpc.DialOption {
+	return grpc.WithContextDialer(func(ctx context.Context, address string) (net.Conn, error) {
+		return l.listener.Dial()
+	})
+}
+
+// Dial opens a connection to the listener.
+func (l *Listener) Dial(ctx context.Context) (*grpc.ClientConn, error) {
+	return grpc.DialContext(ctx, "bufnet", grpc.WithInsecure(), l.DialOption())
+}
+
+// Start serves the encounter service on an in-memory listener until Stop is called.
+func (s *Server) Start() {
+	s.listener = Serve(s)
+}
+
+// Stop closes every stream and the listener.
+func (s *Server) Stop() {
+	s.listener.Stop()
+}
+
+// DialOption connects a client to the server whatever address it dials.
+func (s *Server) DialOption() grpc.DialOption {
+	return s.listener.DialOption()
+}
+
+// Dial opens a connection to the server.
+func (s *Server) Dial(ctx context.Context) (*grpc.ClientConn, error) {
+	return s.listener.Dial(ctx)
+}
+
+// Done is closed once the match is completed.
+func (s *Server) Done() <-chan struct{} {
+	return s.done
+}
+
+// Encounter returns a copy of the current state of the match.
+func (s *Server) Encounter() *deviant.Encounter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.match.Encounter()
+}
+
+// Winner returns the winning alignment once the match is over, or false while it is running or when nobody won.
+func (s *Server) Winner() (deviant.Alignment, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.match.Winner()
+}
+
+// Turns returns the number of turns that have ended.
+func (s *Server) Turns() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.match.Turns()
+}
+
+// Registrations returns the players in the order they registered, including registrations after reconnecting.
+func (s *Server) Registrations() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string{}, s.registrations...)
+}
+
+// Events returns every request received after registration, in the order they were handled.
+func (s *Server) Events() []Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Event{}, s.events...)
+}
+
+// send sends the current state to a player, ignoring streams that have gone away.
+func (s *Server) send(playerID string) {
+	if stream, ok := s.streams[playerID]; ok {
+		stream.Send(s.match.Response(playerID))
+	}
+}
+
+func (s *Server) broadcast() {
+	for playerID := range s.streams {
+		s.send(playerID)
+	}
+}
+
+func (s *Server) register(playerID string, stream deviant.EncounterService_UpdateEncounterServer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.streams[playerID] = stream
+	s.registrations = append(s.registrations, playerID)
+
+	if s.started {
+		s.send(playerID)
+		return
+	}
+
+	for player := range s.players {
+		if _, ok := s.streams[player]; !ok {
+			return
+		}
+	}
+
+	s.started = true
+	s.broadcast()
+}
+
+func (s *Server) unregister(playerID string, stream deviant.EncounterService_UpdateEncounterServer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.streams[playerID] == stream {
+		delete(s.streams, playerID)
+	}
+}
+
+func (s *Server) handle(playerID string, request *deviant.EncounterRequest) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event := Event{PlayerID: playerID, Request: proto.Clone(request).(*deviant.EncounterRequest)}
+
+	active := s.match.Active()
+	event.ActiveEntityID = active.Id
+	if turn := s.match.Encounter().Turn; turn != nil {
+		event.TurnID = turn.Id
+	}
+
+	switch {
+	case !s.started:
+		event.Err = fmt.Errorf("the encounter has not started")
+	case request.PlayerId != playerID:
+		event.Err = fmt.Errorf("request for player %s on the stream of %s", request.PlayerId, playerID)
+	case active.OwnerId != playerID:
+		event.Err = fmt.Errorf("it is %s's turn, not %s's", active.OwnerId, playerID)
+	default:
+		event.Err = s.match.Apply(request)
+	}
+
+	if event.Err == nil && s.MaxTurns > 0 && s.match.Turns() >= s.MaxTurns {
+		s.match.Stop()
+	}
+
+	s.events = append(s.events, event)
+
+	if event.Err != nil {
+		s.send(playerID)
+	} else {
+		s.broadcast()
+	}
+
+	if s.match.Completed() {
+		select {
+		case <-s.done:
+		default:
+			close(s.done)
+		}
+	}
+}
+
+// UpdateEncounter registers the stream's player and applies its requests to the match.
+func (s *Server) UpdateEncounter(stream deviant.EncounterService_UpdateEncounterServer) error {
+	registration, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	if registration.EncounterCreateAction == nil || registration.PlayerId == "" {
+		return status.Error(codes.InvalidArgument, "the first request must register a player")
+	}
+
+	playerID := registration.PlayerId
+	if !s.players[playerID] {
+		return status.Errorf(codes.PermissionDenied, "player %s has no entities in the encounter", playerID)
+	}
+
+	s.register(playerID, stream)
+	defer s.unregister(playerID, stream)
+
+	for {
+		request, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		s.handle(playerID, request)
+	}
+}
@@ -0,0 +1,218 @@
+package fakeserver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/recluse-games/deviant-glados/client"
+	"github.com/recluse-games/deviant-glados/encgen"
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func startServer(t *testing.T) *Server {
+	config := encgen.DefaultConfig()
+	config.Friendly, config.Unfriendly = 1, 1
+
+	encounter, err := encgen.Generate(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server, err := New(encounter)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server.Start()
+	t.Cleanup(server.Stop)
+
+	return server
+}
+
+func open(t *testing.T, server *Server) deviant.EncounterService_UpdateEncounterClient {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	conn, err := server.Dial(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		conn.Close()
+	})
+
+	stream, err := deviant.NewEncounterServiceClient(conn).UpdateEncounter(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return stream
+}
+
+func register(t *testing.T, server *Server, playerID string) deviant.EncounterService_UpdateEncounterClient {
+	stream := open(t, server)
+	if err := stream.Send(client.RegistrationRequest(playerID)); err != nil {
+		t.Fatal(err)
+	}
+
+	return stream
+}
+
+// receive waits for the next response on a stream.
+func receive(t *testing.T, stream deviant.EncounterService_UpdateEncounterClient) *deviant.EncounterResponse {
+	t.Helper()
+
+	responses := make(chan *deviant.EncounterResponse, 1)
+	errs := make(chan error, 1)
+	go func() {
+		response, err := stream.Recv()
+		if err != nil {
+			errs <- err
+			return
+		}
+
+		responses <- response
+	}()
+
+	select {
+	case response := <-responses:
+		return response
+	case err := <-errs:
+		t.Fatal(err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a response")
+	}
+
+	return nil
+}
+
+func endTurn(playerID string) *deviant.EncounterRequest {
+	return &deviant.EncounterRequest{PlayerId: playerID, EntityActionName: deviant.EntityActionNames_CHANGE_PHASE}
+}
+
+func TestUpdateEncounterRequiresRegistration(t *testing.T) {
+	server := startServer(t)
+
+	stream := open(t, server)
+	stream.Send(endTurn(encgen.FriendlyPlayerID))
+	if _, err := stream.Recv(); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected a stream without registration to be refused, got %v", err)
+	}
+
+	stream = register(t, server, "0009")
+	if _, err := stream.Recv(); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected a player without entities to be refused, got %v", err)
+	}
+}
+
+func TestUpdateEncounterStartsOnceEveryoneRegistered(t *testing.T) {
+	server := startServer(t)
+
+	friendly := register(t, server, encgen.FriendlyPlayerID)
+	waitFor(t, func() bool { return len(server.Registrations()) == 1 })
+
+	// The match has not started, so requests are refused.
+	friendly.Send(endTurn(encgen.FriendlyPlayerID))
+	if response := receive(t, friendly); response.Encounter.Turn.Id != "turn_0000" {
+		t.Fatalf("expected the refused request to leave the first turn, got %v", response.Encounter.Turn)
+	}
+
+	unfriendly := register(t, server, encgen.UnfriendlyPlayerID)
+
+	for _, stream := range []deviant.EncounterService_UpdateEncounterClient{friendly, unfriendly} {
+		if response := receive(t, stream); response.Encounter.ActiveEntity.OwnerId != encgen.FriendlyPlayerID {
+			t.Fatalf("expected the friendly player to start, got %v", response.Encounter.ActiveEntity)
+		}
+	}
+
+	if events := server.Events(); len(events) != 1 || events[0].Err == nil {
+		t.Errorf("expected the early request to be refused, got %+v", events)
+	}
+}
+
+func TestUpdateEncounterEnforcesTurns(t *testing.T) {
+	server := startServer(t)
+
+	friendly := register(t, server, encgen.FriendlyPlayerID)
+	unfriendly := register(t, server, encgen.UnfriendlyPlayerID)
+	receive(t, friendly)
+	receive(t, unfriendly)
+
+	// Only the sender hears about a request out of turn.
+	unfriendly.Send(endTurn(encgen.UnfriendlyPlayerID))
+	if response := receive(t, unfriendly); response.Encounter.ActiveEntity.OwnerId != encgen.FriendlyPlayerID {
+		t.Fatalf("expected the turn to stay with the friendly player, got %v", response.Encounter.ActiveEntity)
+	}
+
+	// Everybody hears about the end of a turn.
+	friendly.Send(endTurn(encgen.FriendlyPlayerID))
+	for _, stream := range []deviant.EncounterService_UpdateEncounterClient{friendly, unfriendly} {
+		if response := receive(t, stream); response.Encounter.ActiveEntity.OwnerId != encgen.UnfriendlyPlayerID || response.Encounter.Turn.Id != "turn_0001" {
+			t.Fatalf("expected the unfriendly player's turn, got %v", response.Encounter.Turn)
+		}
+	}
+
+	events := server.Events()
+	if len(events) != 2 || events[0].Err == nil || events[1].Err != nil || events[1].TurnID != "turn_0000" {
+		t.Errorf("unexpected events %+v", events)
+	}
+}
+
+func TestUpdateEncounterEndsAfterMaxTurns(t *testing.T) {
+	server := startServer(t)
+	server.MaxTurns = 1
+
+	friendly := register(t, server, encgen.FriendlyPlayerID)
+	register(t, server, encgen.UnfriendlyPlayerID)
+	receive(t, friendly)
+
+	friendly.Send(endTurn(encgen.FriendlyPlayerID))
+	if response := receive(t, friendly); !response.Encounter.Completed {
+		t.Fatal("expected the match to end after a turn")
+	}
+
+	select {
+	case <-server.Done():
+	default:
+		t.Error("expected the server to be done")
+	}
+
+	if _, won := server.Winner(); won {
+		t.Error("expected a match out of turns to have no winner")
+	}
+}
+
+func TestUpdateEncounterReregisters(t *testing.T) {
+	server := startServer(t)
+
+	register(t, server, encgen.FriendlyPlayerID)
+	unfriendly := register(t, server, encgen.UnfriendlyPlayerID)
+	receive(t, unfriendly)
+
+	// A second stream for the same player is sent the current state straight away.
+	again := register(t, server, encgen.UnfriendlyPlayerID)
+	if response := receive(t, again); response.PlayerId != encgen.UnfriendlyPlayerID {
+		t.Fatalf("expected the state for the reconnected player, got %v", response.PlayerId)
+	}
+
+	if registrations := server.Registrations(); len(registrations) != 3 {
+		t.Errorf("expected three registrations, got %v", registrations)
+	}
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for condition")
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,62 @@
+// Package fixture builds the small hand-made encounters that tests across the repository plan turns on.
+package fixture
+
+import (
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+)
+
+// Slash builds a 9x8 board where the friendly 0001, holding a single slash, can reach and hit the unfriendly 0002
+// four tiles below it. 0001 is the active entity.
+func Slash() *deviant.Encounter {
+	hero := &deviant.Entity{
+		Id:        "0001",
+		Hp:        10,
+		MaxHp:     10,
+		Ap:        5,
+		MaxAp:     5,
+		Alignment: deviant.Alignment_FRIENDLY,
+		OwnerId:   "0001",
+		Hand: &deviant.Hand{
+			Cards: []*deviant.Card{
+				{
+					Id:         "attack_slash_0000",
+					InstanceId: "slash_0000",
+					Cost:       2,
+					Damage:     2,
+					Action: &deviant.CardAction{
+						Pattern: []*deviant.Pattern{
+							{
+								Direction: deviant.Direction_DOWN,
+								Distance:  3,
+								Offset: []*deviant.Offset{
+									{Direction: deviant.Direction_DOWN, Distance: 1},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	rows := []*deviant.EntitiesRow{}
+	for x := 0; x < 9; x++ {
+		row := &deviant.EntitiesRow{}
+		for y := 0; y < 8; y++ {
+			row.Entities = append(row.Entities, &deviant.Entity{})
+		}
+		rows = append(rows, row)
+	}
+
+	rows[0].Entities[0] = hero
+	rows[4].Entities[0] = &deviant.Entity{Id: "0002", Hp: 4, MaxHp: 10, Alignment: deviant.Alignment_UNFRIENDLY}
+
+	return &deviant.Encounter{
+		Id: "encounter_0000",
+		Board: &deviant.Board{
+			Entities: &deviant.Entities{Entities: rows},
+		},
+		ActiveEntity: hero,
+		Turn:         &deviant.Turn{Id: "turn_0000"},
+	}
+}
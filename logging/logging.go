@@ -0,0 +1,320 @@
+// Package logging is the leveled, structured logger threaded through the bots and the planner.
+//
+// Library code logs through a Logger it is handed and is silent when it is handed none, so that only commands decide
+// where log lines go and how they look. Entries carry fields, such as the encounter, turn and entity they concern,
+// and are written either as plain text or as one JSON object per line.
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level how important an entry is. Loggers drop entries below their level.
+type Level int
+
+// Levels in increasing order of importance.
+const (
+	Debug Level = iota
+	Info
+	Warn
+	Error
+)
+
+var levelNames = map[Level]string{
+	Debug: "debug",
+	Info:  "info",
+	Warn:  "warn",
+	Error: "error",
+}
+
+func (l Level) String() string {
+	if name, ok := levelNames[l]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("Level(%d)", int(l))
+}
+
+// ParseLevel parses a level name such as "info".
+func ParseLevel(name string) (Level, error) {
+	for level, levelName := range levelNames {
+		if strings.EqualFold(name, levelName) {
+			return level, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown log level %q, expected debug, info, warn or error", name)
+}
+
+// Format how entries are written.
+type Format string
+
+// Formats supported by New.
+const (
+	Text Format = "text"
+	JSON Format = "json"
+)
+
+// ParseFormat parses a format name such as "json".
+func ParseFormat(name string) (Format, error) {
+	switch format := Format(strings.ToLower(name)); format {
+	case Text, JSON:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unknown log format %q, expected text or json", name)
+	}
+}
+
+// Flags the command line flags every command configures its logger with.
+type Flags struct {
+	Level  string
+	Format string
+}
+
+// RegisterFlags binds -log-level and -log-format, defaulting to info entries written as text.
+func (f *Flags) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(&f.Level, "log-level", "info", "least important entries to log: debug, info, warn or error")
+	fs.StringVar(&f.Format, "log-format", string(Text), "how to write log entries: text or json")
+}
+
+// Logger returns the logger the flags describe, writing to writer.
+func (f *Flags) Logger(writer io.Writer) (Logger, error) {
+	level, err := ParseLevel(f.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	format, err := ParseFormat(f.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	return New(writer, format, level), nil
+}
+
+// Field a key and value attached to an entry.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// Keys of the fields shared across packages, so that every entry about the same match can be found together.
+const (
+	EncounterKey = "encounter"
+	TurnKey      = "turn"
+	EntityKey    = "entity"
+	PlayerKey    = "player"
+	StrategyKey  = "strategy"
+	ErrorKey     = "error"
+)
+
+// Any returns a field with an arbitrary value.
+func Any(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Encounter returns the field naming the encounter an entry concerns.
+func Encounter(id string) Field {
+	return Field{Key: EncounterKey, Value: id}
+}
+
+// Turn returns the field naming the turn an entry concerns.
+func Turn(id string) Field {
+	return Field{Key: TurnKey, Value: id}
+}
+
+// Entity returns the field naming the entity an entry concerns.
+func Entity(id string) Field {
+	return Field{Key: EntityKey, Value: id}
+}
+
+// Player returns the field naming the player an entry concerns.
+func Player(id string) Field {
+	return Field{Key: PlayerKey, Value: id}
+}
+
+// Strategy returns the field naming the strategy an entry concerns.
+func Strategy(name string) Field {
+	return Field{Key: StrategyKey, Value: name}
+}
+
+// Err returns the field holding an error.
+func Err(err error) Field {
+	return Field{Key: ErrorKey, Value: err}
+}
+
+// Logger writes leveled entries with fields. Implementations must be safe for concurrent use.
+type Logger interface {
+	Debug(message string, fields ...Field)
+	Info(message string, fields ...Field)
+	Warn(message string, fields ...Field)
+	Error(message string, fields ...Field)
+	// With returns a logger adding fields to every entry.
+	With(fields ...Field) Logger
+}
+
+// nop a logger that drops everything.
+type nop struct{}
+
+func (nop) Debug(string, ...Field) {}
+func (nop) Info(string, ...Field)  {}
+func (nop) Warn(string, ...Field)  {}
+func (nop) Error(string, ...Field) {}
+
+func (n nop) With(...Field) Logger {
+	return n
+}
+
+// Nop returns a logger that drops everything.
+func Nop() Logger {
+	return nop{}
+}
+
+// OrNop returns logger, or a logger that drops everything when it is nil.
+func OrNop(logger Logger) Logger {
+	if logger == nil {
+		return nop{}
+	}
+
+	return logger
+}
+
+// output the writer shared by a logger and every logger derived from it with With.
+type output struct {
+	mu     sync.Mutex
+	writer io.Writer
+	format Format
+	level  Level
+	now    func() time.Time
+}
+
+// logger writes entries at or above its output's level.
+type logger struct {
+	output *output
+	fields []Field
+}
+
+// New returns a logger writing entries at level and above to writer in format.
+func New(writer io.Writer, format Format, level Level) Logger {
+	return &logger{output: &output{writer: writer, format: format, level: level, now: time.Now}}
+}
+
+func (l *logger) Debug(message string, fields ...Field) {
+	l.log(Debug, message, fields)
+}
+
+func (l *logger) Info(message string, fields ...Field) {
+	l.log(Info, message, fields)
+}
+
+func (l *logger) Warn(message string, fields ...Field) {
+	l.log(Warn, message, fields)
+}
+
+func (l *logger) Error(message string, fields ...Field) {
+	l.log(Error, message, fields)
+}
+
+func (l *logger) With(fields ...Field) Logger {
+	combined := make([]Field, 0, len(l.fields)+len(fields))
+	combined = append(combined, l.fields...)
+	combined = append(combined, fields...)
+
+	return &logger{output: l.output, fields: combined}
+}
+
+func (l *logger) log(level Level, message string, fields []Field) {
+	if level < l.output.level {
+		return
+	}
+
+	all := append(append([]Field{}, l.fields...), fields...)
+	// Later fields replace earlier ones with the same key, and every entry lists its fields in the same order.
+	byKey := map[string]interface{}{}
+	for _, field := range all {
+		byKey[field.Key] = field.Value
+	}
+
+	keys := make([]string, 0, len(byKey))
+	for key := range byKey {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	buffer := &bytes.Buffer{}
+	now := l.output.now().UTC().Format(time.RFC3339Nano)
+
+	if l.output.format == JSON {
+		writeJSON(buffer, now, level, message, keys, byKey)
+	} else {
+		writeText(buffer, now, level, message, keys, byKey)
+	}
+
+	l.output.mu.Lock()
+	defer l.output.mu.Unlock()
+
+	l.output.writer.Write(buffer.Bytes())
+}
+
+// plain returns the value errors and fmt.Stringers are written as.
+func plain(value interface{}) interface{} {
+	switch value := value.(type) {
+	case error:
+		return value.Error()
+	case fmt.Stringer:
+		return value.String()
+	default:
+		return value
+	}
+}
+
+func writeText(buffer *bytes.Buffer, now string, level Level, message string, keys []string, fields map[string]interface{}) {
+	fmt.Fprintf(buffer, "%s %s %s", now, strings.ToUpper(level.String()), message)
+
+	for _, key := range keys {
+		value := fmt.Sprint(plain(fields[key]))
+		if value == "" || strings.ContainsAny(value, " =\"\n\t") {
+			value = strconv.Quote(value)
+		}
+
+		fmt.Fprintf(buffer, " %s=%s", key, value)
+	}
+
+	buffer.WriteByte('\n')
+}
+
+func writeJSON(buffer *bytes.Buffer, now string, level Level, message string, keys []string, fields map[string]interface{}) {
+	entry := []Field{{"time", now}, {"level", level.String()}, {"msg", message}}
+	for _, key := range keys {
+		entry = append(entry, Field{key, fields[key]})
+	}
+
+	buffer.WriteByte('{')
+	for i, field := range entry {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+
+		key, _ := json.Marshal(field.Key)
+		value, err := json.Marshal(plain(field.Value))
+		if err != nil {
+			value, _ = json.Marshal(fmt.Sprint(field.Value))
+		}
+
+		buffer.Write(key)
+		buffer.WriteByte(':')
+		buffer.Write(value)
+	}
+
+	buffer.WriteString("}\n")
+}
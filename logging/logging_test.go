@@ -0,0 +1,189 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fixed returns a logger writing to buffer at a fixed time.
+func fixed(buffer *bytes.Buffer, format Format, level Level) Logger {
+	l := New(buffer, format, level).(*logger)
+	l.output.now = func() time.Time {
+		return time.Date(2020, 6, 14, 12, 30, 0, 0, time.UTC)
+	}
+
+	return l
+}
+
+func TestTextFormat(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	l := fixed(buffer, Text, Info).With(Encounter("encounter_0000"), Player("0001"))
+
+	l.Info("Request rejected, replanning", Turn("turn_0003"), Err(errors.New("tile is occupied")), Any("delay", 250*time.Millisecond))
+	l.Warn("Empty", Any("note", ""))
+
+	want := `2020-06-14T12:30:00Z INFO Request rejected, replanning delay=250ms encounter=encounter_0000 error="tile is occupied" player=0001 turn=turn_0003
+2020-06-14T12:30:00Z WARN Empty encounter=encounter_0000 note="" player=0001
+`
+	if got := buffer.String(); got != want {
+		t.Fatalf("expected\n%s\ngot\n%s", want, got)
+	}
+}
+
+func TestJSONFormat(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	l := fixed(buffer, JSON, Debug).With(Entity("0001"), Strategy("hunting"))
+
+	l.Debug("Planned turn", Any("candidates", 12), Any("unencodable", make(chan int)), Entity("0002"))
+
+	entry := map[string]interface{}{}
+	if err := json.Unmarshal(buffer.Bytes(), &entry); err != nil {
+		t.Fatalf("expected a JSON object, got %q: %v", buffer.String(), err)
+	}
+
+	want := map[string]interface{}{
+		"time":       "2020-06-14T12:30:00Z",
+		"level":      "debug",
+		"msg":        "Planned turn",
+		"candidates": float64(12),
+		"entity":     "0002",
+		"strategy":   "hunting",
+	}
+	for key, value := range want {
+		if entry[key] != value {
+			t.Errorf("expected %s to be %v, got %v", key, value, entry[key])
+		}
+	}
+
+	if _, ok := entry["unencodable"].(string); !ok {
+		t.Errorf("expected values JSON cannot encode to be written as text, got %v", entry["unencodable"])
+	}
+
+	if !strings.HasPrefix(buffer.String(), `{"time":`) || strings.Count(buffer.String(), "\n") != 1 {
+		t.Errorf("expected a single line starting with the time, got %q", buffer.String())
+	}
+}
+
+func TestLevelFiltering(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	l := fixed(buffer, Text, Warn)
+
+	l.Debug("debug")
+	l.Info("info")
+	l.Warn("warn")
+	l.Error("error")
+
+	if got := buffer.String(); strings.Contains(got, "debug") || strings.Contains(got, "info") || strings.Count(got, "\n") != 2 {
+		t.Fatalf("expected only warnings and errors, got\n%s", got)
+	}
+}
+
+func TestWithDoesNotShareFields(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	base := fixed(buffer, Text, Info).With(Player("0001"))
+
+	first := base.With(Turn("turn_0001"))
+	second := base.With(Turn("turn_0002"))
+	first.Info("first")
+	second.Info("second")
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if len(lines) != 2 || !strings.HasSuffix(lines[0], "turn=turn_0001") || !strings.HasSuffix(lines[1], "turn=turn_0002") {
+		t.Fatalf("expected each derived logger to keep its own fields, got %q", lines)
+	}
+}
+
+func TestConcurrentEntriesStayWhole(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	l := New(buffer, JSON, Info)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			for j := 0; j < 50; j++ {
+				l.With(Any("writer", i)).Info("entry", Any("index", j))
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	for _, line := range strings.Split(strings.TrimSpace(buffer.String()), "\n") {
+		if !json.Valid([]byte(line)) {
+			t.Fatalf("expected every line to be a whole entry, got %q", line)
+		}
+	}
+}
+
+func TestNopAndOrNop(t *testing.T) {
+	Nop().With(Player("0001")).Error("dropped")
+
+	if _, ok := OrNop(nil).(nop); !ok {
+		t.Fatal("expected a nil logger to be replaced by one that drops everything")
+	}
+
+	buffer := &bytes.Buffer{}
+	l := New(buffer, Text, Info)
+	if OrNop(l) != l {
+		t.Fatal("expected a logger to be kept")
+	}
+}
+
+func TestParse(t *testing.T) {
+	if level, err := ParseLevel("WARN"); err != nil || level != Warn {
+		t.Errorf("expected warn, got %v %v", level, err)
+	}
+
+	if _, err := ParseLevel("loud"); err == nil {
+		t.Error("expected unknown levels to be rejected")
+	}
+
+	if format, err := ParseFormat("json"); err != nil || format != JSON {
+		t.Errorf("expected json, got %v %v", format, err)
+	}
+
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("expected unknown formats to be rejected")
+	}
+
+	if got := Level(9).String(); got != "Level(9)" {
+		t.Errorf("expected unnamed levels to show their value, got %q", got)
+	}
+}
+
+func TestFlags(t *testing.T) {
+	flags := &Flags{}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.RegisterFlags(fs)
+
+	if err := fs.Parse([]string{"-log-level", "warn", "-log-format", "json"}); err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := &bytes.Buffer{}
+	l, err := flags.Logger(buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l.Info("dropped")
+	l.Warn("kept")
+
+	if got := buffer.String(); strings.Count(got, "\n") != 1 || !strings.Contains(got, `"msg":"kept"`) {
+		t.Fatalf("expected a single JSON warning, got %q", got)
+	}
+
+	if _, err := (&Flags{Level: "loud", Format: "text"}).Logger(buffer); err == nil {
+		t.Error("expected an unknown level to be rejected")
+	}
+}
@@ -1,90 +1,36 @@
 package main
 
 import (
-	"context"
 	"flag"
-	"io"
 	"log"
+	"os"
 	"time"
 
-	channels "github.com/eapache/channels"
-
-	"github.com/recluse-games/deviant-glados/hunting"
-	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
-	"google.golang.org/grpc"
+	"github.com/recluse-games/deviant-glados/bot"
+	"github.com/recluse-games/deviant-glados/client"
+	"github.com/recluse-games/deviant-glados/strategy"
 )
 
-var playerID *string
-
-// HACK: REMOVE THIS ONCE WE HAVE PROPER REGISTRATION.
-func createEncounter(playerID *string) *deviant.EncounterRequest {
-	encounterRequest := &deviant.EncounterRequest{}
-	encounterRequest.EncounterCreateAction = &deviant.EncounterCreateAction{}
-	encounterRequest.PlayerId = *playerID
-
-	return encounterRequest
-}
-
 func main() {
-	playerID = flag.String("id", "0000", "a playerId ")
-	flag.Parse()
-
-	conn, err := grpc.Dial("127.0.0.1:50051", grpc.WithInsecure())
+	config, err := client.ConfigFromEnv()
 	if err != nil {
-		panic(err)
+		log.Fatalf("Invalid environment: %v", err)
 	}
 
-	client := deviant.NewEncounterServiceClient(conn)
-	ch := channels.NewRingChannel(0) // yes this is rather silly, but it should work
-
-	stream, err := client.UpdateEncounter(context.Background())
-	// HACK Used to add the bot to the players table.
-	if err := stream.Send(createEncounter(playerID)); err != nil {
-		log.Fatalf("Failed to send a note: %v", err)
-	}
-	waitc := make(chan struct{})
-
-	go func() {
-		for {
-			in, err := stream.Recv()
-			if err == io.EOF {
-				// read done.
-				return
-			}
-
-			ch.In() <- in
-
-			if err != nil {
-				log.Fatalf("Failed to receive a note : %v", err)
-			}
-		}
-	}()
-	go func() {
-		for {
-			singleEncounterRes := <-ch.Out()
-			if singleEncounterRes != nil {
-				if singleEncounterRes.(*deviant.EncounterResponse).Encounter.ActiveEntity.OwnerId == *playerID {
-					log.Printf("Current Active Entity %v", singleEncounterRes.(*deviant.EncounterResponse).Encounter.ActiveEntity.Id)
-					hunt := deviant.Alignment_UNFRIENDLY
-
-					if *playerID == "0001" {
-						hunt = deviant.Alignment_UNFRIENDLY
-					} else {
-						hunt = deviant.Alignment_FRIENDLY
-					}
-
-					for _, request := range hunting.TakeTurn(singleEncounterRes.(*deviant.EncounterResponse), hunt) {
-						request.PlayerId = *playerID
-						log.Printf("Sending Request: %v", request)
-						time.Sleep(500 * time.Millisecond)
-						if err := stream.Send(request); err != nil {
-							log.Fatalf("Failed to send a note: %v", err)
-						}
-					}
-				}
-			}
-		}
-	}()
+	config.RegisterFlags(flag.CommandLine)
+
+	opts := &bot.Options{}
+	flag.StringVar(&opts.PlayerID, "id", "0000", "a playerId ")
+	flag.BoolVar(&opts.Trace, "trace", false, "log a JSON decision trace for every turn")
+	flag.DurationVar(&opts.AckTimeout, "ack-timeout", 5*time.Second, "how long to wait for the server to acknowledge each action, 0 to send turns blindly")
+	flag.StringVar(&opts.Pace, "pace", "fixed:500ms", "pause before each action: none, fixed:<duration>, think or think:<min>:<max>")
+	flag.StringVar(&opts.Difficulty, "difficulty", strategy.DefaultDifficulty, "how well the bot plays: easy, normal, hard or nightmare")
+	flag.Int64Var(&opts.Seed, "seed", 0, "seed for any randomness in the bot's play, recorded in its decision traces")
+	flag.StringVar(&opts.Record, "record", "", "record every response received and request sent to a file per encounter in this directory")
+	flag.StringVar(&opts.Metrics, "metrics", "", "serve Prometheus metrics on /metrics at this address, such as :9090")
+	flag.StringVar(&opts.Bots, "bots", "", "run every bot described by a JSON manager configuration instead of a single bot")
+	opts.Logging.RegisterFlags(flag.CommandLine)
+	flag.Parse()
 
-	<-waitc
+	os.Exit(bot.RunMain(opts, config, os.Stdout, os.Stderr))
 }
@@ -0,0 +1,166 @@
+// Package metrics exports what bots do as Prometheus metrics: the turns they play, how long planning takes and how
+// many candidates it weighs, and how often their streams drop, their requests are rejected and they win or lose.
+//
+// Every metric is labelled with the strategy and difficulty the bot plays with, so that strategies can be compared
+// while many bots share one process.
+package metrics
+
+import (
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Namespace prefixes the name of every metric.
+const Namespace = "glados"
+
+// Path the path metrics are served on.
+const Path = "/metrics"
+
+var labels = []string{"strategy", "difficulty"}
+
+// Metrics the collectors shared by every bot in a process.
+type Metrics struct {
+	turns      *prometheus.CounterVec
+	planning   *prometheus.HistogramVec
+	candidates *prometheus.HistogramVec
+	reconnects *prometheus.CounterVec
+	rejected   *prometheus.CounterVec
+	matches    *prometheus.CounterVec
+}
+
+// New creates the collectors and registers them with registerer.
+func New(registerer prometheus.Registerer) (*Metrics, error) {
+	m := &Metrics{
+		turns: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "turns_played_total",
+			Help:      "Turns the bots played to the end.",
+		}, labels),
+		planning: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "planning_seconds",
+			Help:      "How long planning a turn took.",
+			Buckets:   prometheus.ExponentialBuckets(0.001, 4, 8),
+		}, labels),
+		candidates: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "plan_candidates",
+			Help:      "How many candidate actions were weighed while planning a turn.",
+			Buckets:   prometheus.ExponentialBuckets(1, 4, 8),
+		}, labels),
+		reconnects: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "stream_reconnects_total",
+			Help:      "Times an encounter stream was lost and opened again.",
+		}, labels),
+		rejected: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "rejected_actions_total",
+			Help:      "Requests the server did not apply.",
+		}, labels),
+		matches: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "matches_total",
+			Help:      "Completed encounters by how they ended for the bot.",
+		}, append(labels, "outcome")),
+	}
+
+	for _, collector := range []prometheus.Collector{m.turns, m.planning, m.candidates, m.reconnects, m.rejected, m.matches} {
+		if err := registerer.Register(collector); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+// Bot returns the metrics of a bot playing strategy at difficulty, nil when m is nil.
+func (m *Metrics) Bot(strategy string, difficulty string) *Bot {
+	if m == nil {
+		return nil
+	}
+
+	values := prometheus.Labels{"strategy": strategy, "difficulty": difficulty}
+
+	return &Bot{
+		turns:      m.turns.With(values),
+		planning:   m.planning.With(values),
+		candidates: m.candidates.With(values),
+		reconnects: m.reconnects.With(values),
+		rejected:   m.rejected.With(values),
+		matches:    m.matches.MustCurryWith(values),
+	}
+}
+
+// Bot the metrics of a single strategy and difficulty. A nil *Bot records nothing.
+type Bot struct {
+	turns      prometheus.Counter
+	planning   prometheus.Observer
+	candidates prometheus.Observer
+	reconnects prometheus.Counter
+	rejected   prometheus.Counter
+	matches    *prometheus.CounterVec
+}
+
+// TurnPlayed counts a turn played to the end.
+func (b *Bot) TurnPlayed() {
+	if b != nil {
+		b.turns.Inc()
+	}
+}
+
+// Planned records how long a plan took and how many candidates it weighed.
+func (b *Bot) Planned(elapsed time.Duration, candidates int) {
+	if b != nil {
+		b.planning.Observe(elapsed.Seconds())
+		b.candidates.Observe(float64(candidates))
+	}
+}
+
+// Reconnected counts a lost stream.
+func (b *Bot) Reconnected() {
+	if b != nil {
+		b.reconnects.Inc()
+	}
+}
+
+// Rejected counts a request the server did not apply.
+func (b *Bot) Rejected() {
+	if b != nil {
+		b.rejected.Inc()
+	}
+}
+
+// Completed counts a completed encounter with the given outcome, such as won or lost.
+func (b *Bot) Completed(outcome string) {
+	if b != nil {
+		b.matches.WithLabelValues(outcome).Inc()
+	}
+}
+
+// Handler serves the metrics gathered by gatherer in the Prometheus text format.
+func Handler(gatherer prometheus.Gatherer) http.Handler {
+	return promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{})
+}
+
+// Serve serves the metrics gathered by gatherer on Path at address until the returned server is closed. The server's
+// Addr is the address it actually listens on, which differs from address when that leaves the port to the system.
+func Serve(address string, gatherer prometheus.Gatherer) (*http.Server, error) {
+	mux := http.NewServeMux()
+	mux.Handle(Path, Handler(gatherer))
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	server := &http.Server{Addr: listener.Addr().String(), Handler: mux}
+
+	go server.Serve(listener)
+
+	return server, nil
+}
@@ -0,0 +1,109 @@
+package metrics
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestBotMetrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	m, err := New(registry)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hunting := m.Bot("hunting", "normal")
+	hunting.TurnPlayed()
+	hunting.TurnPlayed()
+	hunting.Planned(20*time.Millisecond, 12)
+	hunting.Reconnected()
+	hunting.Rejected()
+	hunting.Completed("won")
+	m.Bot("hunting", "easy").Completed("lost")
+
+	counters := map[string]struct {
+		counter prometheus.Collector
+		want    float64
+	}{
+		"turns":      {m.turns.WithLabelValues("hunting", "normal"), 2},
+		"reconnects": {m.reconnects.WithLabelValues("hunting", "normal"), 1},
+		"rejected":   {m.rejected.WithLabelValues("hunting", "normal"), 1},
+		"won":        {m.matches.WithLabelValues("hunting", "normal", "won"), 1},
+		"lost":       {m.matches.WithLabelValues("hunting", "easy", "lost"), 1},
+		"not lost":   {m.matches.WithLabelValues("hunting", "normal", "lost"), 0},
+	}
+
+	for name, c := range counters {
+		if got := testutil.ToFloat64(c.counter); got != c.want {
+			t.Errorf("%s: expected %v, got %v", name, c.want, got)
+		}
+	}
+
+	// Each bot's series exist from the start, so that they read zero rather than missing.
+	if count := testutil.CollectAndCount(m.planning); count != 2 {
+		t.Errorf("expected a planning histogram for each bot, got %d", count)
+	}
+
+	if problems, err := testutil.GatherAndLint(registry); err != nil || len(problems) != 0 {
+		t.Errorf("expected metrics to follow Prometheus conventions, got %v %v", problems, err)
+	}
+}
+
+func TestNilBotRecordsNothing(t *testing.T) {
+	var m *Metrics
+	b := m.Bot("hunting", "normal")
+
+	b.TurnPlayed()
+	b.Planned(time.Second, 1)
+	b.Reconnected()
+	b.Rejected()
+	b.Completed("won")
+}
+
+func TestNewRejectsDuplicateRegistration(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	if _, err := New(registry); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := New(registry); err == nil {
+		t.Fatal("expected registering the collectors twice to fail")
+	}
+}
+
+func TestServe(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	m, err := New(registry)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m.Bot("hunting", "normal").TurnPlayed()
+
+	server, err := Serve("127.0.0.1:0", registry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	response, err := http.Get("http://" + server.Addr + Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := `glados_turns_played_total{difficulty="normal",strategy="hunting"} 1`; !strings.Contains(string(body), want) {
+		t.Fatalf("expected %q in\n%s", want, body)
+	}
+}
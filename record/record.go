@@ -0,0 +1,279 @@
+// Package record captures everything a bot receives from and sends to the encounter server, so that a live match
+// can be examined and replayed after the fact.
+//
+// A recording is a JSON lines file with one Entry per line, holding either a received EncounterResponse or a sent
+// EncounterRequest along with the time it passed through the bot. A Recorder starts a new file whenever the
+// encounter changes, so every file holds a single match.
+package record
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/recluse-games/deviant-glados/logging"
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// maxLine bounds the length of a single recorded entry, which holds a whole encounter.
+const maxLine = 16 << 20
+
+// Entry a single message in a recording. Exactly one of Response and Request is set.
+type Entry struct {
+	Time     time.Time
+	Response *deviant.EncounterResponse
+	Request  *deviant.EncounterRequest
+	// Error is why a request failed to send, empty when it was sent.
+	Error string
+}
+
+// line the JSON form of an entry, with the messages in their protobuf JSON form.
+type line struct {
+	Time     time.Time       `json:"time"`
+	Response json.RawMessage `json:"response,omitempty"`
+	Request  json.RawMessage `json:"request,omitempty"`
+	Error    string          `json:"error,omitempty"`
+}
+
+// MarshalJSON encodes the entry with its messages in their protobuf JSON form.
+func (e *Entry) MarshalJSON() ([]byte, error) {
+	l := line{Time: e.Time, Error: e.Error}
+
+	var err error
+	switch {
+	case e.Response != nil:
+		l.Response, err = protojson.Marshal(e.Response)
+	case e.Request != nil:
+		l.Request, err = protojson.Marshal(e.Request)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(l)
+}
+
+// UnmarshalJSON decodes an entry written by MarshalJSON.
+func (e *Entry) UnmarshalJSON(data []byte) error {
+	l := line{}
+	if err := json.Unmarshal(data, &l); err != nil {
+		return err
+	}
+
+	*e = Entry{Time: l.Time, Error: l.Error}
+
+	switch {
+	case len(l.Response) > 0:
+		e.Response = &deviant.EncounterResponse{}
+		return protojson.Unmarshal(l.Response, e.Response)
+	case len(l.Request) > 0:
+		e.Request = &deviant.EncounterRequest{}
+		return protojson.Unmarshal(l.Request, e.Request)
+	}
+
+	return errors.New("entry holds neither a response nor a request")
+}
+
+// Read decodes every entry of a recording.
+func Read(r io.Reader) ([]*Entry, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxLine)
+
+	entries := []*Entry{}
+	for number := 1; scanner.Scan(); number++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+
+		entry := &Entry{}
+		if err := json.Unmarshal(scanner.Bytes(), entry); err != nil {
+			return nil, fmt.Errorf("line %d: %v", number, err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, scanner.Err()
+}
+
+// ReadFile decodes every entry of a recording file.
+func ReadFile(path string) ([]*Entry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	entries, err := Read(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+
+	return entries, nil
+}
+
+// FileName returns the name of the file recording a player's encounter.
+func FileName(playerID string, encounterID string) string {
+	clean := func(id string) string {
+		return strings.Map(func(r rune) rune {
+			if r == '/' || r == '\\' || r == os.PathSeparator {
+				return '_'
+			}
+
+			return r
+		}, id)
+	}
+
+	return fmt.Sprintf("%s-%s.jsonl", clean(playerID), clean(encounterID))
+}
+
+// Recorder writes a player's stream to a directory, one file per encounter. It is safe for concurrent use. Failures
+// to record are logged rather than returned so that recording never interrupts a match.
+type Recorder struct {
+	Dir      string
+	PlayerID string
+	// Now stamps every entry, time.Now when nil.
+	Now func() time.Time
+	// Logger receives the entries about failures to record, nothing is logged when it is nil.
+	Logger logging.Logger
+
+	mu          sync.Mutex
+	encounterID string
+	file        *os.File
+	// pending holds entries seen before the first encounter, which are written to its file.
+	pending []*Entry
+}
+
+// New creates a recorder writing to dir, creating the directory if needed.
+func New(dir string, playerID string) (*Recorder, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	return &Recorder{Dir: dir, PlayerID: playerID}, nil
+}
+
+func (r *Recorder) now() time.Time {
+	if r.Now == nil {
+		return time.Now()
+	}
+
+	return r.Now()
+}
+
+func (r *Recorder) log() logging.Logger {
+	return logging.OrNop(r.Logger).With(logging.Player(r.PlayerID))
+}
+
+// rotate switches to the file of encounterID, appending to it if it already exists.
+func (r *Recorder) rotate(encounterID string) error {
+	if r.file != nil {
+		if err := r.file.Close(); err != nil {
+			r.log().Error("Failed to close recording", logging.Encounter(r.encounterID), logging.Err(err))
+		}
+
+		r.file = nil
+	}
+
+	r.encounterID = encounterID
+
+	file, err := os.OpenFile(filepath.Join(r.Dir, FileName(r.PlayerID, encounterID)), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	r.file = file
+
+	return nil
+}
+
+func (r *Recorder) write(entry *Entry, encounterID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if encounterID != "" && (r.file == nil || encounterID != r.encounterID) {
+		if err := r.rotate(encounterID); err != nil {
+			r.log().Error("Failed to start recording", logging.Encounter(encounterID), logging.Err(err))
+		}
+	}
+
+	if r.file == nil {
+		r.pending = append(r.pending, entry)
+		return
+	}
+
+	entries := append(r.pending, entry)
+	r.pending = nil
+
+	for _, entry := range entries {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			r.log().Error("Failed to encode recorded entry", logging.Err(err))
+			continue
+		}
+
+		if _, err := r.file.Write(append(data, '\n')); err != nil {
+			r.log().Error("Failed to record", logging.Any("file", r.file.Name()), logging.Err(err))
+		}
+	}
+}
+
+// Received records a response from the server.
+func (r *Recorder) Received(response *deviant.EncounterResponse) {
+	r.write(&Entry{Time: r.now(), Response: response}, response.GetEncounter().GetId())
+}
+
+// Sent records a request sent to the server along with the error sending it, if any.
+func (r *Recorder) Sent(request *deviant.EncounterRequest, err error) {
+	entry := &Entry{Time: r.now(), Request: request}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	r.write(entry, "")
+}
+
+// OnResponse wraps a response handler so that every response is recorded before it is handled.
+func (r *Recorder) OnResponse(handle func(*deviant.EncounterResponse)) func(*deviant.EncounterResponse) {
+	return func(response *deviant.EncounterResponse) {
+		r.Received(response)
+
+		if handle != nil {
+			handle(response)
+		}
+	}
+}
+
+// Send wraps a send function so that every request is recorded once it has been sent.
+func (r *Recorder) Send(send func(*deviant.EncounterRequest) error) func(*deviant.EncounterRequest) error {
+	return func(request *deviant.EncounterRequest) error {
+		err := send(request)
+		r.Sent(request, err)
+
+		return err
+	}
+}
+
+// Close closes the current recording file.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return nil
+	}
+
+	err := r.file.Close()
+	r.file = nil
+
+	return err
+}
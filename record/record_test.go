@@ -0,0 +1,200 @@
+package record
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+	"google.golang.org/protobuf/proto"
+)
+
+func generateResponse(encounterID string, turnID string) *deviant.EncounterResponse {
+	return &deviant.EncounterResponse{
+		Encounter: &deviant.Encounter{
+			Id:   encounterID,
+			Turn: &deviant.Turn{Id: turnID, Phase: deviant.TurnPhaseNames_PHASE_ACTION},
+		},
+	}
+}
+
+func endTurn(playerID string) *deviant.EncounterRequest {
+	return &deviant.EncounterRequest{PlayerId: playerID, EntityActionName: deviant.EntityActionNames_CHANGE_PHASE}
+}
+
+// clock returns a clock that ticks a second every time it is read.
+func clock() func() time.Time {
+	now := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	return func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+}
+
+func newRecorder(t *testing.T) *Recorder {
+	dir, err := ioutil.TempDir("", "record")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		os.RemoveAll(dir)
+	})
+
+	recorder, err := New(filepath.Join(dir, "recordings"), "0001")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recorder.Now = clock()
+
+	return recorder
+}
+
+func readEntries(t *testing.T, recorder *Recorder, encounterID string) []*Entry {
+	entries, err := ReadFile(filepath.Join(recorder.Dir, FileName(recorder.PlayerID, encounterID)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return entries
+}
+
+func TestEntryJSON(t *testing.T) {
+	entries := []*Entry{
+		{Time: time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC), Response: generateResponse("encounter_0000", "turn_0001")},
+		{Time: time.Date(2020, 6, 1, 12, 0, 1, 0, time.UTC), Request: endTurn("0001"), Error: "not connected"},
+	}
+
+	var buffer bytes.Buffer
+	for _, entry := range entries {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if bytes.Contains(data, []byte("\n")) {
+			t.Fatalf("expected an entry on a single line, got %s", data)
+		}
+
+		buffer.Write(append(data, '\n'))
+	}
+
+	read, err := Read(&buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(read) != len(entries) {
+		t.Fatalf("expected %d entries, got %d", len(entries), len(read))
+	}
+
+	for i := range entries {
+		if !read[i].Time.Equal(entries[i].Time) || read[i].Error != entries[i].Error ||
+			!proto.Equal(read[i].Response, entries[i].Response) || !proto.Equal(read[i].Request, entries[i].Request) {
+			t.Errorf("entry %d changed from %+v to %+v", i, entries[i], read[i])
+		}
+	}
+}
+
+func TestReadRejectsEmptyEntries(t *testing.T) {
+	if _, err := Read(strings.NewReader("{\"time\":\"2020-06-01T12:00:00Z\"}\n")); err == nil {
+		t.Error("expected an entry without a message to be rejected")
+	}
+}
+
+func TestRecorderRotatesByEncounter(t *testing.T) {
+	recorder := newRecorder(t)
+
+	recorder.Received(generateResponse("encounter_0000", "turn_0001"))
+	recorder.Sent(endTurn("0001"), nil)
+	recorder.Received(generateResponse("encounter_0001", "turn_0001"))
+	recorder.Sent(endTurn("0001"), errors.New("not connected"))
+
+	if err := recorder.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	first := readEntries(t, recorder, "encounter_0000")
+	if len(first) != 2 || first[0].Response == nil || first[1].Request == nil || first[1].Error != "" {
+		t.Errorf("expected a response and a sent request in the first encounter, got %+v", first)
+	}
+
+	second := readEntries(t, recorder, "encounter_0001")
+	if len(second) != 2 || second[0].Response.Encounter.Id != "encounter_0001" || second[1].Error != "not connected" {
+		t.Errorf("expected a response and a failed request in the second encounter, got %+v", second)
+	}
+
+	if !first[0].Time.Before(first[1].Time) || !first[1].Time.Before(second[0].Time) {
+		t.Error("expected entries to be stamped in order")
+	}
+}
+
+func TestRecorderKeepsEntriesBeforeTheFirstEncounter(t *testing.T) {
+	recorder := newRecorder(t)
+
+	recorder.Sent(endTurn("0001"), nil)
+	recorder.Received(&deviant.EncounterResponse{})
+	recorder.Received(generateResponse("encounter_0000", "turn_0001"))
+	recorder.Close()
+
+	entries := readEntries(t, recorder, "encounter_0000")
+	if len(entries) != 3 || entries[0].Request == nil || entries[2].Response.Encounter.Id != "encounter_0000" {
+		t.Errorf("expected earlier entries at the start of the first encounter, got %+v", entries)
+	}
+}
+
+func TestRecorderAppendsToAnEarlierRecording(t *testing.T) {
+	recorder := newRecorder(t)
+
+	recorder.Received(generateResponse("encounter_0000", "turn_0001"))
+	recorder.Close()
+	recorder.Received(generateResponse("encounter_0000", "turn_0002"))
+	recorder.Close()
+
+	if entries := readEntries(t, recorder, "encounter_0000"); len(entries) != 2 {
+		t.Errorf("expected both responses after reconnecting, got %d", len(entries))
+	}
+}
+
+func TestRecorderWraps(t *testing.T) {
+	recorder := newRecorder(t)
+
+	handled := 0
+	onResponse := recorder.OnResponse(func(*deviant.EncounterResponse) {
+		handled++
+	})
+
+	sent := 0
+	send := recorder.Send(func(*deviant.EncounterRequest) error {
+		sent++
+		return nil
+	})
+
+	onResponse(generateResponse("encounter_0000", "turn_0001"))
+	if err := send(endTurn("0001")); err != nil {
+		t.Fatal(err)
+	}
+	recorder.Close()
+
+	if handled != 1 || sent != 1 {
+		t.Errorf("expected the wrapped functions to be called once each, got %d and %d", handled, sent)
+	}
+
+	if entries := readEntries(t, recorder, "encounter_0000"); len(entries) != 2 {
+		t.Errorf("expected 2 entries, got %d", len(entries))
+	}
+}
+
+func TestFileName(t *testing.T) {
+	if name := FileName("0001", "../encounter/0000"); name != "0001-.._encounter_0000.jsonl" {
+		t.Errorf("expected ids to stay inside the directory, got %s", name)
+	}
+}
@@ -0,0 +1,260 @@
+// Package render draws encounter boards as text for logs, traces and the command line tools, optionally
+// overlaying the tiles an entity can reach, the path it walks and the tiles its cards hit.
+package render
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/recluse-games/deviant-glados/astar"
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+)
+
+const (
+	cellWidth = 9
+	labelIDs  = 4
+
+	ansiReset  = "\x1b[0m"
+	ansiBold   = "\x1b[1m"
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+	ansiCyan   = "\x1b[36m"
+)
+
+// Overlay markers drawn on empty tiles.
+const (
+	MarkEmpty     = "."
+	MarkReachable = "~"
+	MarkPath      = "*"
+	MarkHit       = "x"
+)
+
+// Point a board location using the same row (X) and column (Y) convention as the encounter entities.
+type Point struct {
+	X int
+	Y int
+}
+
+// Options controls what is drawn on top of the board.
+type Options struct {
+	Color     bool
+	Reachable []Point
+	Path      []Point
+	Hits      []Point
+}
+
+func contains(points []Point, x int, y int) bool {
+	for _, point := range points {
+		if point.X == x && point.Y == y {
+			return true
+		}
+	}
+
+	return false
+}
+
+func colorFor(alignment deviant.Alignment) string {
+	switch alignment {
+	case deviant.Alignment_FRIENDLY:
+		return ansiGreen
+	case deviant.Alignment_UNFRIENDLY:
+		return ansiRed
+	}
+
+	return ansiYellow
+}
+
+func entityLabel(entity *deviant.Entity) string {
+	id := entity.Id
+	if len(id) > labelIDs {
+		id = id[:labelIDs]
+	}
+
+	return fmt.Sprintf("%s:%d", id, entity.Hp)
+}
+
+func pad(label string) string {
+	if len(label) >= cellWidth {
+		return label[:cellWidth]
+	}
+
+	left := (cellWidth - len(label)) / 2
+
+	return strings.Repeat(" ", left) + label + strings.Repeat(" ", cellWidth-len(label)-left)
+}
+
+func cell(encounter *deviant.Encounter, entity *deviant.Entity, x int, y int, options *Options) string {
+	hit := contains(options.Hits, x, y)
+
+	if entity.Id != "" {
+		label := entityLabel(entity)
+		if hit {
+			label = MarkHit + label + MarkHit
+		}
+
+		text := pad(label)
+		if !options.Color {
+			return text
+		}
+
+		color := colorFor(entity.Alignment)
+		if encounter.ActiveEntity != nil && encounter.ActiveEntity.Id == entity.Id {
+			color = ansiBold + color
+		}
+
+		return color + text + ansiReset
+	}
+
+	mark := MarkEmpty
+	switch {
+	case hit:
+		mark = MarkHit
+	case contains(options.Path, x, y):
+		mark = MarkPath
+	case contains(options.Reachable, x, y):
+		mark = MarkReachable
+	}
+
+	text := pad(mark)
+	if options.Color && mark != MarkEmpty {
+		return ansiCyan + text + ansiReset
+	}
+
+	return text
+}
+
+// Fprint draws the encounter board to w.
+func Fprint(w io.Writer, encounter *deviant.Encounter, options *Options) error {
+	_, err := io.WriteString(w, Board(encounter, options))
+
+	return err
+}
+
+// Board draws the encounter board as text, one line per row of entities.
+func Board(encounter *deviant.Encounter, options *Options) string {
+	if options == nil {
+		options = &Options{}
+	}
+
+	builder := &strings.Builder{}
+
+	if encounter == nil || encounter.Board == nil || encounter.Board.Entities == nil {
+		return ""
+	}
+
+	rows := encounter.Board.Entities.Entities
+	columns := 0
+	for _, row := range rows {
+		if len(row.Entities) > columns {
+			columns = len(row.Entities)
+		}
+	}
+
+	builder.WriteString("   ")
+	for y := 0; y < columns; y++ {
+		builder.WriteString(pad(fmt.Sprintf("%d", y)))
+	}
+	builder.WriteString("\n")
+
+	for x, row := range rows {
+		builder.WriteString(fmt.Sprintf("%2d ", x))
+
+		for y := 0; y < columns; y++ {
+			entity := &deviant.Entity{}
+			if y < len(row.Entities) && row.Entities[y] != nil {
+				entity = row.Entities[y]
+			}
+
+			builder.WriteString(cell(encounter, entity, x, y, options))
+		}
+
+		builder.WriteString("\n")
+	}
+
+	return builder.String()
+}
+
+// Path finds the tiles walked between two points, avoiding occupied tiles. The start is excluded and the end is
+// included. If no path can be found the straight line end point is returned on its own.
+func Path(encounter *deviant.Encounter, start Point, end Point) []Point {
+	rows := encounter.Board.Entities.Entities
+	if len(rows) == 0 {
+		return nil
+	}
+
+	grid := [][]astar.Node{}
+	for y := 0; y < len(rows[0].Entities); y++ {
+		column := []astar.Node{}
+
+		for x := 0; x < len(rows); x++ {
+			walkable := true
+			if y < len(rows[x].Entities) && rows[x].Entities[y].Id != "" {
+				walkable = false
+			}
+
+			column = append(column, astar.Node{
+				Position: &astar.Vertex{
+					X: x,
+					Y: y,
+				},
+				Walkable: walkable,
+			})
+		}
+
+		grid = append(grid, column)
+	}
+
+	pathfinder := &astar.Astar{
+		Grid: grid,
+	}
+
+	stack := pathfinder.FindPath(&astar.Vertex{X: start.X, Y: start.Y}, &astar.Vertex{X: end.X, Y: end.Y}, len(rows)*len(rows[0].Entities))
+	if stack == nil {
+		return []Point{end}
+	}
+
+	path := []Point{}
+	for node := stack.Pop(); node != nil; node = stack.Pop() {
+		path = append(path, Point{
+			X: node.Position.X,
+			Y: node.Position.Y,
+		})
+	}
+
+	return path
+}
+
+// PlannedTurn builds overlay options showing the path walked and the tiles hit by a planned sequence of requests.
+func PlannedTurn(encounter *deviant.Encounter, requests []*deviant.EncounterRequest) *Options {
+	options := &Options{}
+
+	for _, request := range requests {
+		if request.EntityMoveAction != nil {
+			start := Point{
+				X: int(request.EntityMoveAction.StartXPosition),
+				Y: int(request.EntityMoveAction.StartYPosition),
+			}
+			end := Point{
+				X: int(request.EntityMoveAction.FinalXPosition),
+				Y: int(request.EntityMoveAction.FinalYPosition),
+			}
+
+			if start != end {
+				options.Path = append(options.Path, Path(encounter, start, end)...)
+			}
+		}
+
+		if request.EntityPlayAction != nil {
+			for _, play := range request.EntityPlayAction.Plays {
+				options.Hits = append(options.Hits, Point{
+					X: int(play.X),
+					Y: int(play.Y),
+				})
+			}
+		}
+	}
+
+	return options
+}
@@ -0,0 +1,112 @@
+package render
+
+import (
+	"strings"
+	"testing"
+
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+)
+
+func generateEncounter() *deviant.Encounter {
+	hero := &deviant.Entity{
+		Id:        "0001",
+		Hp:        10,
+		Alignment: deviant.Alignment_FRIENDLY,
+	}
+
+	return &deviant.Encounter{
+		Id: "encounter_0000",
+		Board: &deviant.Board{
+			Entities: &deviant.Entities{
+				Entities: []*deviant.EntitiesRow{
+					{Entities: []*deviant.Entity{hero, {}, {}, {}}},
+					{Entities: []*deviant.Entity{{}, {Id: "wall_0000", Hp: 2, Alignment: deviant.Alignment_NEUTRAL}, {}, {}}},
+					{Entities: []*deviant.Entity{{}, {}, {}, {Id: "0002", Hp: 7, Alignment: deviant.Alignment_UNFRIENDLY}}},
+				},
+			},
+		},
+		ActiveEntity: hero,
+	}
+}
+
+func TestBoardLabelsEntities(t *testing.T) {
+	board := Board(generateEncounter(), nil)
+
+	for _, label := range []string{"0001:10", "wall:2", "0002:7"} {
+		if !strings.Contains(board, label) {
+			t.Errorf("expected %q in board:\n%s", label, board)
+		}
+	}
+
+	if strings.Contains(board, "\x1b[") {
+		t.Errorf("expected no colour codes without Color:\n%s", board)
+	}
+
+	if lines := strings.Split(strings.TrimRight(board, "\n"), "\n"); len(lines) != 4 {
+		t.Errorf("expected a header and 3 rows, got %v lines:\n%s", len(lines), board)
+	}
+}
+
+func TestBoardColorsAlignments(t *testing.T) {
+	board := Board(generateEncounter(), &Options{Color: true})
+
+	if !strings.Contains(board, ansiRed) || !strings.Contains(board, ansiGreen) || !strings.Contains(board, ansiYellow) {
+		t.Errorf("expected each alignment to be coloured:\n%q", board)
+	}
+}
+
+func TestBoardOverlays(t *testing.T) {
+	board := Board(generateEncounter(), &Options{
+		Reachable: []Point{{X: 0, Y: 1}},
+		Path:      []Point{{X: 0, Y: 2}},
+		Hits:      []Point{{X: 2, Y: 3}, {X: 2, Y: 2}},
+	})
+
+	rows := strings.Split(board, "\n")
+
+	if !strings.Contains(rows[1], MarkReachable) || !strings.Contains(rows[1], MarkPath) {
+		t.Errorf("expected reachable and path markers in first row: %q", rows[1])
+	}
+
+	if !strings.Contains(rows[3], "x0002:7x") || strings.Count(rows[3], MarkHit) != 3 {
+		t.Errorf("expected hit markers in last row: %q", rows[3])
+	}
+}
+
+func TestPlannedTurn(t *testing.T) {
+	encounter := generateEncounter()
+
+	options := PlannedTurn(encounter, []*deviant.EncounterRequest{
+		{
+			EntityMoveAction: &deviant.EntityMoveAction{
+				StartXPosition: 0,
+				StartYPosition: 0,
+				FinalXPosition: 2,
+				FinalYPosition: 2,
+			},
+		},
+		{
+			EntityPlayAction: &deviant.EntityPlayAction{
+				Plays: []*deviant.Play{{X: 2, Y: 3}},
+			},
+		},
+	})
+
+	if len(options.Path) != 4 {
+		t.Fatalf("expected a 4 tile path, got %v", options.Path)
+	}
+
+	if options.Path[len(options.Path)-1] != (Point{X: 2, Y: 2}) {
+		t.Errorf("expected the path to end at the destination, got %v", options.Path)
+	}
+
+	for _, point := range options.Path {
+		if point == (Point{X: 1, Y: 1}) {
+			t.Errorf("path walks through the wall: %v", options.Path)
+		}
+	}
+
+	if len(options.Hits) != 1 || options.Hits[0] != (Point{X: 2, Y: 3}) {
+		t.Errorf("expected the play to be drawn as a hit, got %v", options.Hits)
+	}
+}
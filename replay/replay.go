@@ -0,0 +1,257 @@
+// Package replay runs the bot runtime against a recorded match instead of a server, to check whether the current
+// code still sends what was recorded.
+//
+// The recorded responses are fed to the bot through a fake UpdateEncounter stream in the order they were received,
+// and each recorded request is compared with the next request the bot sends. The first request that differs, is
+// missing or was never recorded ends the replay. Registrations are left out of the comparison, since the replay opens
+// a single stream and registers on it once whatever reconnections the recording holds. Responses the bot did not wait for in the original match may reach
+// it at a different point in its turn, so a replay is only as deterministic as the bot's planning and pacing.
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/recluse-games/deviant-glados/bot"
+	"github.com/recluse-games/deviant-glados/record"
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Defaults for a Config left at its zero values.
+const (
+	DefaultWait   = 30 * time.Second
+	DefaultSettle = time.Second
+)
+
+// Config describes how to replay a recording.
+type Config struct {
+	// PlayerID is the recorded player, taken from the first recorded request when empty.
+	PlayerID string
+	Plan     bot.Planner
+	// AckTimeout is passed on to the runtime, and should match the recorded bot's.
+	AckTimeout time.Duration
+	// Wait is how long to wait for each recorded request to be sent again.
+	Wait time.Duration
+	// Settle is how long to wait for requests the bot sends after the recording ends.
+	Settle time.Duration
+}
+
+// Divergence the first point at which a replay differed from its recording.
+type Divergence struct {
+	// Index is the entry where the replay differed, or the number of entries when the bot sent more than was
+	// recorded.
+	Index int
+	// Want is the recorded request, nil when nothing more was recorded.
+	Want *deviant.EncounterRequest
+	// Got is the request sent during the replay, nil when nothing was sent in time.
+	Got *deviant.EncounterRequest
+}
+
+func (d *Divergence) String() string {
+	format := func(request *deviant.EncounterRequest) string {
+		if request == nil {
+			return "nothing"
+		}
+
+		return protojson.Format(request)
+	}
+
+	return fmt.Sprintf("entry %d: sent %s\nrecorded %s", d.Index, format(d.Got), format(d.Want))
+}
+
+// Result the outcome of a replay.
+type Result struct {
+	// Matched is the number of recorded requests sent again unchanged.
+	Matched int
+	// Requests is the number of requests in the recording, not counting registrations.
+	Requests int
+	// Divergence is the first difference from the recording, nil when there was none.
+	Divergence *Divergence
+}
+
+// sent a request sent on the fake stream, waiting to be told whether it failed.
+type sent struct {
+	request *deviant.EncounterRequest
+	result  chan error
+}
+
+// stream a fake UpdateEncounter stream that receives the recorded responses and hands over everything sent.
+type stream struct {
+	grpc.ClientStream
+
+	ctx       context.Context
+	responses chan *deviant.EncounterResponse
+	sent      chan sent
+}
+
+func (s *stream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *stream) Recv() (*deviant.EncounterResponse, error) {
+	select {
+	case response := <-s.responses:
+		return response, nil
+	case <-s.ctx.Done():
+		return nil, io.EOF
+	}
+}
+
+// CloseSend does nothing, the replay ends the stream by cancelling its context.
+func (s *stream) CloseSend() error {
+	return nil
+}
+
+// Send hands the request over and returns the error it failed with in the recording, if any.
+func (s *stream) Send(request *deviant.EncounterRequest) error {
+	if isRegistration(request) {
+		return nil
+	}
+
+	result := make(chan error, 1)
+	select {
+	case s.sent <- sent{request: proto.Clone(request).(*deviant.EncounterRequest), result: result}:
+	case <-s.ctx.Done():
+		return s.ctx.Err()
+	}
+
+	select {
+	case err := <-result:
+		return err
+	case <-s.ctx.Done():
+		return s.ctx.Err()
+	}
+}
+
+// isRegistration reports whether request is one of the registrations the supervisor sends on every new stream.
+func isRegistration(request *deviant.EncounterRequest) bool {
+	return request.EncounterCreateAction != nil
+}
+
+// playerID returns the player of the first recorded request.
+func playerID(entries []*record.Entry) string {
+	for _, entry := range entries {
+		if entry.Request != nil && entry.Request.PlayerId != "" {
+			return entry.Request.PlayerId
+		}
+	}
+
+	return ""
+}
+
+// Run replays entries through a bot runtime configured by config.
+func Run(ctx context.Context, entries []*record.Entry, config *Config) (*Result, error) {
+	if config.Plan == nil {
+		return nil, errors.New("no planner to replay with")
+	}
+
+	id := config.PlayerID
+	if id == "" {
+		if id = playerID(entries); id == "" {
+			return nil, errors.New("the recording holds no requests to tell which player it is")
+		}
+	}
+
+	wait, settle := config.Wait, config.Settle
+	if wait <= 0 {
+		wait = DefaultWait
+	}
+
+	if settle <= 0 {
+		settle = DefaultSettle
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	fake := &stream{
+		ctx:       ctx,
+		responses: make(chan *deviant.EncounterResponse),
+		sent:      make(chan sent),
+	}
+
+	opened := false
+	b, err := bot.New(bot.Config{
+		PlayerID: id,
+		Open: func(ctx context.Context) (deviant.EncounterService_UpdateEncounterClient, error) {
+			if opened {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}
+
+			opened = true
+
+			return fake, nil
+		},
+		Plan:       config.Plan,
+		AckTimeout: config.AckTimeout,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		b.Run(ctx)
+	}()
+	defer func() {
+		<-stopped
+	}()
+	defer cancel()
+
+	result := &Result{}
+	for _, entry := range entries {
+		if entry.Request != nil && !isRegistration(entry.Request) {
+			result.Requests++
+		}
+	}
+
+	for i, entry := range entries {
+		switch {
+		case entry.Request != nil && isRegistration(entry.Request):
+		case entry.Response != nil:
+			select {
+			case fake.responses <- entry.Response:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		case entry.Request != nil:
+			select {
+			case got := <-fake.sent:
+				if !proto.Equal(got.request, entry.Request) {
+					result.Divergence = &Divergence{Index: i, Want: entry.Request, Got: got.request}
+					return result, nil
+				}
+
+				result.Matched++
+				if entry.Error != "" {
+					got.result <- errors.New(entry.Error)
+				} else {
+					got.result <- nil
+				}
+			case <-time.After(wait):
+				result.Divergence = &Divergence{Index: i, Want: entry.Request}
+				return result, nil
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+	}
+
+	select {
+	case got := <-fake.sent:
+		result.Divergence = &Divergence{Index: len(entries), Got: got.request}
+	case <-time.After(settle):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	return result, nil
+}
@@ -0,0 +1,252 @@
+package replay
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/recluse-games/deviant-glados/bot"
+	"github.com/recluse-games/deviant-glados/client"
+	"github.com/recluse-games/deviant-glados/encgen"
+	"github.com/recluse-games/deviant-glados/internal/fakeserver"
+	"github.com/recluse-games/deviant-glados/record"
+	"github.com/recluse-games/deviant-glados/strategy"
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+)
+
+func generateResponse(turnID string, ownerID string) *deviant.EncounterResponse {
+	return &deviant.EncounterResponse{
+		Encounter: &deviant.Encounter{
+			Id:           "encounter_0000",
+			Turn:         &deviant.Turn{Id: turnID, Phase: deviant.TurnPhaseNames_PHASE_ACTION},
+			ActiveEntity: &deviant.Entity{Id: "0003", OwnerId: ownerID},
+		},
+	}
+}
+
+func endTurn() *deviant.EncounterRequest {
+	return &deviant.EncounterRequest{PlayerId: "0001", EntityActionName: deviant.EntityActionNames_CHANGE_PHASE}
+}
+
+func move() *deviant.EncounterRequest {
+	return &deviant.EncounterRequest{
+		PlayerId:         "0001",
+		EntityActionName: deviant.EntityActionNames_MOVE,
+		EntityMoveAction: &deviant.EntityMoveAction{FinalXPosition: 1, FinalYPosition: 2},
+	}
+}
+
+// planner always plans the same requests.
+func planner(requests ...func() *deviant.EncounterRequest) bot.Planner {
+	return func(ctx context.Context, encounterResponse *deviant.EncounterResponse) (*bot.Plan, error) {
+		plan := &bot.Plan{}
+		for _, request := range requests {
+			plan.Requests = append(plan.Requests, request())
+		}
+
+		return plan, nil
+	}
+}
+
+// recording two turns of player 0001, each ended straight away, around a turn of another player.
+func recording() []*record.Entry {
+	now := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	return []*record.Entry{
+		{Time: now, Response: generateResponse("turn_0001", "0001")},
+		{Time: now.Add(time.Second), Request: endTurn()},
+		{Time: now.Add(2 * time.Second), Response: generateResponse("turn_0002", "0002")},
+		{Time: now.Add(3 * time.Second), Response: generateResponse("turn_0003", "0001")},
+		{Time: now.Add(4 * time.Second), Request: endTurn(), Error: "not connected"},
+	}
+}
+
+func replay(t *testing.T, entries []*record.Entry, config *Config) *Result {
+	t.Helper()
+
+	config.Wait = 200 * time.Millisecond
+	config.Settle = 100 * time.Millisecond
+
+	result, err := Run(context.Background(), entries, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return result
+}
+
+func TestRunMatchesTheRecording(t *testing.T) {
+	result := replay(t, recording(), &Config{Plan: planner(endTurn)})
+
+	if result.Divergence != nil {
+		t.Fatalf("expected no divergence, got %s", result.Divergence)
+	}
+
+	if result.Matched != 2 || result.Requests != 2 {
+		t.Errorf("expected both requests to match, got %d of %d", result.Matched, result.Requests)
+	}
+}
+
+func TestRunReportsDifferentRequests(t *testing.T) {
+	result := replay(t, recording(), &Config{Plan: planner(move, endTurn)})
+
+	if result.Divergence == nil || result.Divergence.Index != 1 || result.Divergence.Got.EntityMoveAction == nil {
+		t.Fatalf("expected the move to diverge from the recorded end of turn, got %+v", result.Divergence)
+	}
+
+	if result.Matched != 0 {
+		t.Errorf("expected no requests to match, got %d", result.Matched)
+	}
+}
+
+func TestRunReportsMissingRequests(t *testing.T) {
+	result := replay(t, recording(), &Config{Plan: planner()})
+
+	if result.Divergence == nil || result.Divergence.Index != 1 || result.Divergence.Got != nil {
+		t.Fatalf("expected the recorded end of turn to be missing, got %+v", result.Divergence)
+	}
+}
+
+func TestRunReportsRequestsAfterTheRecording(t *testing.T) {
+	entries := recording()[:1]
+
+	if _, err := Run(context.Background(), entries, &Config{Plan: planner(endTurn)}); err == nil {
+		t.Error("expected a recording without requests to need a player id")
+	}
+
+	result := replay(t, entries, &Config{PlayerID: "0001", Plan: planner(endTurn)})
+
+	if result.Divergence == nil || result.Divergence.Index != 1 || result.Divergence.Want != nil || result.Divergence.Got == nil {
+		t.Fatalf("expected an unrecorded request after the recording, got %+v", result.Divergence)
+	}
+}
+
+func TestRunSkipsRegistrations(t *testing.T) {
+	entries := append([]*record.Entry{{Request: client.RegistrationRequest("0001")}}, recording()...)
+
+	result := replay(t, entries, &Config{Plan: planner(endTurn)})
+
+	if result.Divergence != nil {
+		t.Fatalf("expected the registration to be skipped, got %s", result.Divergence)
+	}
+
+	if result.Matched != 2 || result.Requests != 2 {
+		t.Errorf("expected both requests but not the registration to count, got %d of %d", result.Matched, result.Requests)
+	}
+}
+
+// recordMatch plays a whole match between two bots on the fake server, recording the friendly one into dir.
+func recordMatch(t *testing.T, dir string) *deviant.Encounter {
+	config := encgen.DefaultConfig()
+	config.Mirror = true
+	config.Seed = 3
+
+	encounter, err := encgen.Generate(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server, err := fakeserver.New(encounter)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server.MaxTurns = 200
+	server.Start()
+	defer server.Stop()
+
+	conn, err := server.Dial(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for _, id := range []string{encgen.FriendlyPlayerID, encgen.UnfriendlyPlayerID} {
+		botConfig := bot.Config{
+			PlayerID:       id,
+			Open:           client.Shared(conn),
+			AckTimeout:     5 * time.Second,
+			ExitOnComplete: true,
+		}
+
+		if id == encgen.FriendlyPlayerID {
+			botConfig.Record = dir
+		}
+
+		b, err := bot.New(botConfig)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b.Run(ctx)
+		}()
+	}
+
+	select {
+	case <-server.Done():
+	case <-time.After(time.Minute):
+		t.Fatalf("the match did not finish, %d turns were played", server.Turns())
+	}
+
+	cancel()
+	wg.Wait()
+
+	return encounter
+}
+
+func TestRunReplaysARecordedMatch(t *testing.T) {
+	if testing.Short() {
+		t.Skip("plays a whole match")
+	}
+
+	dir, err := ioutil.TempDir("", "replay")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	encounter := recordMatch(t, dir)
+
+	entries, err := record.ReadFile(filepath.Join(dir, record.FileName(encgen.FriendlyPlayerID, encounter.Id)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) == 0 || entries[0].Request == nil || entries[0].Request.EncounterCreateAction == nil {
+		t.Fatal("expected the recording to start with the registration")
+	}
+
+	selected, err := strategy.New(strategy.Default, strategy.DefaultDifficulty, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := Run(context.Background(), entries, &Config{
+		Plan:       bot.StrategyPlanner(selected, nil, nil),
+		AckTimeout: 5 * time.Second,
+		Wait:       5 * time.Second,
+		Settle:     100 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Divergence != nil {
+		t.Fatalf("expected the match to replay as recorded, got %s", result.Divergence)
+	}
+
+	if result.Requests == 0 || result.Matched != result.Requests {
+		t.Errorf("expected every recorded request to be sent again, got %d of %d", result.Matched, result.Requests)
+	}
+}
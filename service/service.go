@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/recluse-games/deviant-glados/gladospb"
+	"github.com/recluse-games/deviant-glados/hunting"
+	"github.com/recluse-games/deviant-glados/logging"
+	"github.com/recluse-games/deviant-glados/strategy"
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server answers questions about encounters using the registered strategies.
+type Server struct {
+	gladospb.UnimplementedGladosServiceServer
+
+	// Logger receives the server's log entries, including its strategies' when they are strategy.Logged. Nothing is
+	// logged when it is nil.
+	Logger logging.Logger
+}
+
+func (s *Server) log(encounter *deviant.Encounter) logging.Logger {
+	return logging.OrNop(s.Logger).With(logging.Encounter(encounter.Id))
+}
+
+// NewServer creates a server.
+func NewServer() *Server {
+	return &Server{}
+}
+
+func validateEncounter(encounter *deviant.Encounter) error {
+	switch {
+	case encounter == nil:
+		return status.Error(codes.InvalidArgument, "an encounter is required")
+	case encounter.ActiveEntity == nil:
+		return status.Error(codes.InvalidArgument, "the encounter has no active entity")
+	case encounter.Board == nil || encounter.Board.Entities == nil || len(encounter.Board.Entities.Entities) == 0:
+		return status.Error(codes.InvalidArgument, "the encounter has no board")
+	case encounter.Completed:
+		return status.Error(codes.FailedPrecondition, "the encounter is already completed")
+	}
+
+	return nil
+}
+
+// SuggestTurn plans the active entity's turn, hunting whoever opposes it, without playing it.
+func (s *Server) SuggestTurn(ctx context.Context, request *gladospb.SuggestTurnRequest) (response *gladospb.SuggestTurnResponse, err error) {
+	if err := validateEncounter(request.Encounter); err != nil {
+		return nil, err
+	}
+
+	selected, err := strategy.New(request.Strategy, request.Difficulty, request.Seed)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	name := request.Strategy
+	if name == "" {
+		name = strategy.Default
+	}
+
+	logger := s.log(request.Encounter).With(logging.Strategy(name))
+	if logged, ok := selected.(strategy.Logged); ok && s.Logger != nil {
+		selected = logged.WithLogger(logger)
+	}
+
+	// Strategies index the board directly, so a malformed board must not take the server down with it.
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			logger.Error("Planning failed", logging.Any("panic", recovered))
+			response, err = nil, status.Error(codes.Internal, fmt.Sprintf("planning failed: %v", recovered))
+		}
+	}()
+
+	logger.Debug("Suggesting a turn", logging.Any("difficulty", request.Difficulty), logging.Any("seed", request.Seed))
+
+	alignment := strategy.OpposingAlignment(request.Encounter.ActiveEntity.Alignment)
+	requests, trace := selected.TakeTurn(&deviant.EncounterResponse{Encounter: request.Encounter}, alignment)
+
+	return newSuggestTurnResponse(requests, trace), nil
+}
+
+// SuggestHints ranks the card plays open to the active entity against whoever opposes it, best first.
+func (s *Server) SuggestHints(ctx context.Context, request *gladospb.SuggestHintsRequest) (response *gladospb.SuggestHintsResponse, err error) {
+	if err := validateEncounter(request.Encounter); err != nil {
+		return nil, err
+	}
+
+	if request.Limit < 0 {
+		return nil, status.Error(codes.InvalidArgument, "the limit must not be negative")
+	}
+
+	logger := s.log(request.Encounter)
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			logger.Error("Ranking hints failed", logging.Any("panic", recovered))
+			response, err = nil, status.Error(codes.Internal, fmt.Sprintf("ranking hints failed: %v", recovered))
+		}
+	}()
+
+	logger.Debug("Suggesting hints", logging.Any("limit", request.Limit))
+
+	alignment := strategy.OpposingAlignment(request.Encounter.ActiveEntity.Alignment)
+
+	response = &gladospb.SuggestHintsResponse{Hints: []*gladospb.Hint{}}
+	for _, hint := range hunting.Hints(request.Encounter, alignment, int(request.Limit)) {
+		response.Hints = append(response.Hints, NewHint(hint))
+	}
+
+	return response, nil
+}
+
+func newPoint(point hunting.TracePoint) *gladospb.Point {
+	return &gladospb.Point{X: int32(point.X), Y: int32(point.Y)}
+}
+
+// NewCandidate converts a traced candidate into its wire form.
+func NewCandidate(candidate *hunting.TraceCandidate) *gladospb.Candidate {
+	return &gladospb.Candidate{
+		CardId:         candidate.CardID,
+		CardInstanceId: candidate.CardInstanceID,
+		Origin:         newPoint(candidate.Origin),
+		MoveCost:       int32(candidate.MoveCost),
+		Rotation:       candidate.Rotation,
+		Target:         newPoint(candidate.Target),
+		TargetId:       candidate.TargetID,
+		TargetHp:       candidate.TargetHp,
+		Score:          int32(candidate.Score),
+	}
+}
+
+func newSuggestTurnResponse(requests []*deviant.EncounterRequest, trace *hunting.Trace) *gladospb.SuggestTurnResponse {
+	response := &gladospb.SuggestTurnResponse{
+		Requests:   requests,
+		Candidates: []*gladospb.Candidate{},
+	}
+
+	if trace == nil {
+		return response
+	}
+
+	for _, candidate := range trace.Candidates {
+		response.Candidates = append(response.Candidates, NewCandidate(candidate))
+	}
+
+	if trace.Choice != nil {
+		response.Choice = trace.Choice.Kind
+	}
+
+	return response
+}
+
+// NewHint converts a hint into its wire form.
+func NewHint(hint *hunting.Hint) *gladospb.Hint {
+	targets := []*gladospb.HintTarget{}
+	for _, target := range hint.Targets {
+		targets = append(targets, &gladospb.HintTarget{
+			Id:       target.ID,
+			Name:     target.Name,
+			Position: newPoint(target.Position),
+			Damage:   target.Damage,
+			Kills:    target.Kills,
+			Enemy:    target.Enemy,
+			Ally:     target.Ally,
+		})
+	}
+
+	return &gladospb.Hint{
+		CardId:         hint.CardID,
+		CardInstanceId: hint.CardInstanceID,
+		Origin:         newPoint(hint.Origin),
+		MoveCost:       int32(hint.MoveCost),
+		Rotation:       hint.Rotation,
+		Targets:        targets,
+		Score:          int32(hint.Score),
+		Reason:         hint.Reason,
+		Requests:       hint.Requests,
+	}
+}
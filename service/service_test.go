@@ -0,0 +1,186 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/recluse-games/deviant-glados/gladospb"
+	"github.com/recluse-games/deviant-glados/hunting"
+	"github.com/recluse-games/deviant-glados/internal/fakeserver"
+	"github.com/recluse-games/deviant-glados/internal/fixture"
+	"github.com/recluse-games/deviant-glados/logging"
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func startServer(t *testing.T) (gladospb.GladosServiceClient, func()) {
+	listener := fakeserver.Listen(func(server *grpc.Server) {
+		gladospb.RegisterGladosServiceServer(server, NewServer())
+	})
+
+	conn, err := listener.Dial(context.Background())
+	if err != nil {
+		listener.Stop()
+		t.Fatal(err)
+	}
+
+	return gladospb.NewGladosServiceClient(conn), func() {
+		conn.Close()
+		listener.Stop()
+	}
+}
+
+func TestSuggestTurn(t *testing.T) {
+	client, stop := startServer(t)
+	defer stop()
+
+	response, err := client.SuggestTurn(context.Background(), &gladospb.SuggestTurnRequest{Encounter: fixture.Slash()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if response.Choice != hunting.ChoicePlayCard {
+		t.Fatalf("expected the slash to be played, got %v", response.Choice)
+	}
+
+	if len(response.Candidates) == 0 || response.Candidates[0].TargetId != "0002" || response.Candidates[0].Score == 0 {
+		t.Fatalf("expected the best candidate to hit 0002, got %v", response.Candidates)
+	}
+
+	played := false
+	for _, request := range response.Requests {
+		if request.EntityPlayAction != nil && request.EntityPlayAction.CardId == "slash_0000" {
+			played = true
+		}
+	}
+
+	if !played {
+		t.Fatalf("expected the planned requests to play the slash, got %v", response.Requests)
+	}
+}
+
+func TestSuggestTurnIsReproducible(t *testing.T) {
+	client, stop := startServer(t)
+	defer stop()
+
+	request := &gladospb.SuggestTurnRequest{Encounter: fixture.Slash(), Difficulty: "easy", Seed: 39}
+
+	expected, err := client.SuggestTurn(context.Background(), request)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 5; i++ {
+		response, err := client.SuggestTurn(context.Background(), request)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !proto.Equal(response, expected) {
+			t.Fatalf("expected the same seed to suggest the same turn, got %v and %v", response, expected)
+		}
+	}
+}
+
+func TestSuggestTurnRejectsInvalidRequests(t *testing.T) {
+	client, stop := startServer(t)
+	defer stop()
+
+	completed := fixture.Slash()
+	completed.Completed = true
+
+	cases := map[string]struct {
+		request *gladospb.SuggestTurnRequest
+		code    codes.Code
+	}{
+		"no encounter":       {&gladospb.SuggestTurnRequest{}, codes.InvalidArgument},
+		"no board":           {&gladospb.SuggestTurnRequest{Encounter: &deviant.Encounter{ActiveEntity: &deviant.Entity{}}}, codes.InvalidArgument},
+		"completed":          {&gladospb.SuggestTurnRequest{Encounter: completed}, codes.FailedPrecondition},
+		"unknown strategy":   {&gladospb.SuggestTurnRequest{Encounter: fixture.Slash(), Strategy: "telepathy"}, codes.InvalidArgument},
+		"unknown difficulty": {&gladospb.SuggestTurnRequest{Encounter: fixture.Slash(), Difficulty: "impossible"}, codes.InvalidArgument},
+	}
+
+	for name, c := range cases {
+		if _, err := client.SuggestTurn(context.Background(), c.request); status.Code(err) != c.code {
+			t.Errorf("%s: expected %v, got %v", name, c.code, err)
+		}
+	}
+}
+
+func TestSuggestTurnSurvivesMalformedBoards(t *testing.T) {
+	client, stop := startServer(t)
+	defer stop()
+
+	// The active entity is missing from its own board.
+	encounter := fixture.Slash()
+	encounter.Board.Entities.Entities[0].Entities[0] = &deviant.Entity{}
+
+	if _, err := client.SuggestTurn(context.Background(), &gladospb.SuggestTurnRequest{Encounter: encounter}); status.Code(err) != codes.Internal {
+		t.Fatalf("expected a malformed board to fail the call, got %v", err)
+	}
+
+	if _, err := client.SuggestTurn(context.Background(), &gladospb.SuggestTurnRequest{Encounter: fixture.Slash()}); err != nil {
+		t.Fatalf("expected the server to keep serving, got %v", err)
+	}
+}
+
+func TestSuggestTurnLogsFailures(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	server := NewServer()
+	server.Logger = logging.New(buffer, logging.Text, logging.Info)
+
+	encounter := fixture.Slash()
+	encounter.Board.Entities.Entities[0].Entities[0] = &deviant.Entity{}
+
+	if _, err := server.SuggestTurn(context.Background(), &gladospb.SuggestTurnRequest{Encounter: encounter}); status.Code(err) != codes.Internal {
+		t.Fatalf("expected a malformed board to fail the call, got %v", err)
+	}
+
+	if logged := buffer.String(); !strings.Contains(logged, "Planning failed") || !strings.Contains(logged, encounter.Id) {
+		t.Errorf("expected the failure to be logged against the encounter, got %q", logged)
+	}
+}
+
+func TestSuggestHints(t *testing.T) {
+	client, stop := startServer(t)
+	defer stop()
+
+	encounter := fixture.Slash()
+	encounter.Board.Entities.Entities[3].Entities[0] = &deviant.Entity{Id: "wall_0000", Hp: 1, Alignment: deviant.Alignment_NEUTRAL}
+
+	response, err := client.SuggestHints(context.Background(), &gladospb.SuggestHintsRequest{Encounter: encounter, Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(response.Hints) != 1 {
+		t.Fatalf("expected a single hint, got %v", response.Hints)
+	}
+
+	hint := response.Hints[0]
+	if hint.CardInstanceId != "slash_0000" || hint.Score != 2 || len(hint.Requests) != 4 {
+		t.Fatalf("expected slashing 0002 to be hinted, got %v", hint)
+	}
+
+	for _, target := range hint.Targets {
+		if enemy := target.Id == "0002"; target.Enemy != enemy || target.Ally {
+			t.Errorf("expected only 0002 to be an enemy and nobody an ally, got %v", target)
+		}
+	}
+
+	cases := map[string]*gladospb.SuggestHintsRequest{
+		"no encounter":   {},
+		"negative limit": {Encounter: fixture.Slash(), Limit: -1},
+	}
+
+	for name, request := range cases {
+		if _, err := client.SuggestHints(context.Background(), request); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("%s: expected %v, got %v", name, codes.InvalidArgument, err)
+		}
+	}
+}
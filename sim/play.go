@@ -0,0 +1,83 @@
+package sim
+
+import (
+	"fmt"
+
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+)
+
+// Player plans the active entity's turn.
+type Player func(encounterResponse *deviant.EncounterResponse) []*deviant.EncounterRequest
+
+// Result the outcome of a played match.
+type Result struct {
+	// Winner is only meaningful when Draw is false.
+	Winner deviant.Alignment
+	Draw   bool
+	Turns  int
+	// Rejected counts requests the rules refused.
+	Rejected int
+	// Failures counts turns forfeited because a player panicked.
+	Failures int
+	// RejectedBy and FailuresBy split Rejected and Failures by the alignment of the player responsible.
+	RejectedBy map[deviant.Alignment]int
+	FailuresBy map[deviant.Alignment]int
+}
+
+// plan asks a player for a turn, turning a panic into an error so that one bad board does not end a tournament.
+func plan(player Player, encounterResponse *deviant.EncounterResponse) (requests []*deviant.EncounterRequest, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("player panicked: %v", recovered)
+		}
+	}()
+
+	return player(encounterResponse), nil
+}
+
+// Play runs the match until it completes or maxTurns turns have ended, asking the player for the active entity's
+// alignment to plan every turn. A turn ends when its player ends it or runs out of requests.
+func (m *Match) Play(players map[deviant.Alignment]Player, maxTurns int) Result {
+	result := Result{RejectedBy: map[deviant.Alignment]int{}, FailuresBy: map[deviant.Alignment]int{}}
+
+	for !m.Completed() && m.Turns() < maxTurns {
+		active := m.Active()
+		turn := m.Turns()
+
+		player, ok := players[active.Alignment]
+		if !ok {
+			m.EndTurn()
+			continue
+		}
+
+		requests, err := plan(player, m.Response(active.OwnerId))
+		if err != nil {
+			result.Failures++
+			result.FailuresBy[active.Alignment]++
+		}
+
+		for _, request := range requests {
+			if m.Completed() || m.Turns() != turn {
+				break
+			}
+
+			if err := m.Apply(request); err != nil {
+				result.Rejected++
+				result.RejectedBy[active.Alignment]++
+			}
+		}
+
+		if !m.Completed() && m.Turns() == turn {
+			m.EndTurn()
+		}
+	}
+
+	m.Stop()
+
+	winner, won := m.Winner()
+	result.Turns = m.Turns()
+	result.Winner = winner
+	result.Draw = !won
+
+	return result
+}
@@ -0,0 +1,58 @@
+package sim
+
+import (
+	"testing"
+
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+)
+
+func TestPlayUntilWon(t *testing.T) {
+	m, _ := New(generateEncounter())
+
+	players := map[deviant.Alignment]Player{
+		deviant.Alignment_FRIENDLY: func(encounterResponse *deviant.EncounterResponse) []*deviant.EncounterRequest {
+			if encounterResponse.Encounter.ActiveEntity.Id != "0001" {
+				return []*deviant.EncounterRequest{endTurn}
+			}
+
+			// The second slash is unaffordable and the match is over before the move.
+			return []*deviant.EncounterRequest{
+				play("slash_0000", [2]int32{4, 0}),
+				move(0, 1),
+			}
+		},
+		deviant.Alignment_UNFRIENDLY: func(encounterResponse *deviant.EncounterResponse) []*deviant.EncounterRequest {
+			return []*deviant.EncounterRequest{move(9, 9), endTurn}
+		},
+	}
+
+	result := m.Play(players, 10)
+	if result.Draw || result.Winner != deviant.Alignment_FRIENDLY {
+		t.Fatalf("expected the friendly alignment to win but got %+v", result)
+	}
+
+	if result.Turns != 1 || result.Rejected != 1 || result.RejectedBy[deviant.Alignment_UNFRIENDLY] != 1 {
+		t.Errorf("expected one turn and one rejected move but got %+v", result)
+	}
+}
+
+func TestPlayRunsOutOfTurns(t *testing.T) {
+	m, _ := New(generateEncounter())
+
+	panics := func(encounterResponse *deviant.EncounterResponse) []*deviant.EncounterRequest {
+		panic("confused")
+	}
+
+	result := m.Play(map[deviant.Alignment]Player{deviant.Alignment_FRIENDLY: panics}, 6)
+	if !result.Draw || result.Turns != 6 {
+		t.Fatalf("expected a draw after 6 turns but got %+v", result)
+	}
+
+	if result.Failures != 4 || result.FailuresBy[deviant.Alignment_FRIENDLY] != 4 {
+		t.Errorf("expected the friendly entities to forfeit 4 turns but got %d", result.Failures)
+	}
+
+	if !m.Completed() {
+		t.Error("expected the match to be stopped")
+	}
+}
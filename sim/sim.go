@@ -0,0 +1,409 @@
+// Package sim approximates the encounter server's rules closely enough to play headless matches between
+// strategies.
+//
+// Entities take turns in initiative order. On its turn an entity recovers its AP and draws a card, then may move
+// across empty tiles for one AP per tile of manhattan distance and play cards from its hand for their cost. A play
+// damages every entity on the tiles it lists; the tiles are trusted rather than checked against the card's pattern.
+// An entity at zero HP leaves the board, and the match ends once only one of the friendly and unfriendly
+// alignments remains.
+package sim
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+	"google.golang.org/protobuf/proto"
+)
+
+// ErrCompleted is returned when a request arrives after the match has ended.
+var ErrCompleted = errors.New("the encounter is completed")
+
+// Match the state of a single simulated encounter.
+type Match struct {
+	encounter *deviant.Encounter
+	turns     int
+	draw      bool
+}
+
+// New starts a match from a copy of encounter. The encounter's active entity keeps its turn and remaining AP, or
+// the entity with the highest initiative starts when there is none.
+func New(encounter *deviant.Encounter) (*Match, error) {
+	if encounter.Board == nil || encounter.Board.Entities == nil {
+		return nil, errors.New("the encounter has no board")
+	}
+
+	m := &Match{
+		encounter: proto.Clone(encounter).(*deviant.Encounter),
+	}
+
+	order := m.initiativeOrder()
+	if len(order) == 0 {
+		return nil, errors.New("the encounter has no entities that take turns")
+	}
+
+	m.encounter.ActiveEntityOrder = order
+
+	if m.encounter.Turn == nil {
+		m.encounter.Turn = &deviant.Turn{Id: turnID(0)}
+	}
+
+	if m.encounter.ActiveEntity == nil {
+		m.startTurn(order[0])
+	} else {
+		entity, _, _ := m.find(m.encounter.ActiveEntity.Id)
+		if entity == nil {
+			return nil, fmt.Errorf("active entity %s is not on the board", m.encounter.ActiveEntity.Id)
+		}
+
+		m.encounter.ActiveEntity = entity
+	}
+
+	m.checkCompleted()
+
+	return m, nil
+}
+
+func turnID(turn int) string {
+	return fmt.Sprintf("turn_%04d", turn)
+}
+
+// initiativeOrder lists the entities that take turns, highest initiative first and then by id.
+func (m *Match) initiativeOrder() []string {
+	entities := []*deviant.Entity{}
+
+	for _, row := range m.encounter.Board.Entities.Entities {
+		for _, entity := range row.Entities {
+			if entity.Id != "" && entity.OwnerId != "" {
+				entities = append(entities, entity)
+			}
+		}
+	}
+
+	sort.SliceStable(entities, func(i, j int) bool {
+		if entities[i].Initiative != entities[j].Initiative {
+			return entities[i].Initiative > entities[j].Initiative
+		}
+
+		return entities[i].Id < entities[j].Id
+	})
+
+	order := []string{}
+	for _, entity := range entities {
+		order = append(order, entity.Id)
+	}
+
+	return order
+}
+
+// find locates an entity on the board by id.
+func (m *Match) find(id string) (*deviant.Entity, int, int) {
+	for x, row := range m.encounter.Board.Entities.Entities {
+		for y, entity := range row.Entities {
+			if entity.Id == id {
+				return entity, x, y
+			}
+		}
+	}
+
+	return nil, -1, -1
+}
+
+func (m *Match) inBounds(x int, y int) bool {
+	rows := m.encounter.Board.Entities.Entities
+
+	return x >= 0 && x < len(rows) && y >= 0 && y < len(rows[x].Entities)
+}
+
+func (m *Match) at(x int, y int) *deviant.Entity {
+	return m.encounter.Board.Entities.Entities[x].Entities[y]
+}
+
+// Encounter returns a copy of the current state.
+func (m *Match) Encounter() *deviant.Encounter {
+	return proto.Clone(m.encounter).(*deviant.Encounter)
+}
+
+// Response returns a copy of the current state as the server would send it to playerID.
+func (m *Match) Response(playerID string) *deviant.EncounterResponse {
+	encounter := m.Encounter()
+
+	if encounter.ActiveEntity != nil {
+		for _, row := range encounter.Board.Entities.Entities {
+			for _, entity := range row.Entities {
+				if entity.Id == encounter.ActiveEntity.Id {
+					encounter.ActiveEntity = entity
+				}
+			}
+		}
+	}
+
+	return &deviant.EncounterResponse{PlayerId: playerID, Encounter: encounter}
+}
+
+// Active returns the entity whose turn it is.
+func (m *Match) Active() *deviant.Entity {
+	return m.encounter.ActiveEntity
+}
+
+// Turns returns the number of turns that have ended.
+func (m *Match) Turns() int {
+	return m.turns
+}
+
+// Completed reports whether the match is over.
+func (m *Match) Completed() bool {
+	return m.encounter.Completed
+}
+
+// Winner returns the winning alignment once the match is over, or false while it is running or when nobody won.
+func (m *Match) Winner() (deviant.Alignment, bool) {
+	if !m.encounter.Completed || m.draw {
+		return 0, false
+	}
+
+	return m.encounter.WinningAlignment, true
+}
+
+// Apply performs a single request for the active entity, returning an error and leaving the match unchanged when
+// the rules do not allow it.
+func (m *Match) Apply(request *deviant.EncounterRequest) error {
+	if m.encounter.Completed {
+		return ErrCompleted
+	}
+
+	switch {
+	case request.EntityMoveAction != nil:
+		return m.move(request.EntityMoveAction)
+	case request.EntityPlayAction != nil:
+		return m.play(request.EntityPlayAction)
+	case request.EntityTargetAction != nil:
+		// Targeting only highlights tiles for other players.
+		return nil
+	case request.EntityActionName == deviant.EntityActionNames_CHANGE_PHASE:
+		m.EndTurn()
+		return nil
+	}
+
+	return fmt.Errorf("unsupported request %v", request)
+}
+
+// reachable returns the manhattan cost of moving to (x, y) across empty tiles, or false when it cannot be
+// reached with ap.
+func (m *Match) reachable(fromX int, fromY int, x int, y int, ap int32) (int32, bool) {
+	type tile struct{ x, y int }
+
+	cost := func(t tile) int32 {
+		return int32(abs(t.x-fromX) + abs(t.y-fromY))
+	}
+
+	seen := map[tile]bool{{fromX, fromY}: true}
+	queue := []tile{{fromX, fromY}}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		if current.x == x && current.y == y {
+			return cost(current), true
+		}
+
+		for _, next := range []tile{{current.x + 1, current.y}, {current.x - 1, current.y}, {current.x, current.y + 1}, {current.x, current.y - 1}} {
+			if seen[next] || !m.inBounds(next.x, next.y) || m.at(next.x, next.y).Id != "" || cost(next) > ap {
+				continue
+			}
+
+			seen[next] = true
+			queue = append(queue, next)
+		}
+	}
+
+	return 0, false
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+
+	return value
+}
+
+func (m *Match) move(move *deviant.EntityMoveAction) error {
+	active := m.encounter.ActiveEntity
+	_, fromX, fromY := m.find(active.Id)
+
+	x, y := int(move.FinalXPosition), int(move.FinalYPosition)
+	if x == fromX && y == fromY {
+		return nil
+	}
+
+	if !m.inBounds(x, y) {
+		return fmt.Errorf("(%d, %d) is off the board", x, y)
+	}
+
+	if m.at(x, y).Id != "" {
+		return fmt.Errorf("(%d, %d) is occupied by %s", x, y, m.at(x, y).Id)
+	}
+
+	cost, ok := m.reachable(fromX, fromY, x, y, active.Ap)
+	if !ok {
+		return fmt.Errorf("(%d, %d) is out of reach with %d ap", x, y, active.Ap)
+	}
+
+	rows := m.encounter.Board.Entities.Entities
+	rows[x].Entities[y], rows[fromX].Entities[fromY] = active, &deviant.Entity{}
+	active.Ap -= cost
+
+	return nil
+}
+
+func (m *Match) play(play *deviant.EntityPlayAction) error {
+	active := m.encounter.ActiveEntity
+	if active.Hand == nil {
+		return fmt.Errorf("card %s is not in hand", play.CardId)
+	}
+
+	index := -1
+	for i, card := range active.Hand.Cards {
+		if card.InstanceId == play.CardId {
+			index = i
+			break
+		}
+	}
+
+	if index == -1 {
+		return fmt.Errorf("card %s is not in hand", play.CardId)
+	}
+
+	card := active.Hand.Cards[index]
+	if card.Cost > active.Ap {
+		return fmt.Errorf("card %s costs %d ap but only %d ap is available", card.Id, card.Cost, active.Ap)
+	}
+
+	active.Ap -= card.Cost
+	active.Hand.Cards = append(active.Hand.Cards[:index], active.Hand.Cards[index+1:]...)
+	if active.Discard == nil {
+		active.Discard = &deviant.Discard{}
+	}
+	active.Discard.Cards = append(active.Discard.Cards, card)
+
+	// Each entity is only hit once, however many of its tiles the play lists.
+	hit := map[string]bool{}
+	for _, tile := range play.Plays {
+		x, y := int(tile.X), int(tile.Y)
+		if !m.inBounds(x, y) {
+			continue
+		}
+
+		target := m.at(x, y)
+		if target.Id == "" || target.Id == active.Id || hit[target.Id] {
+			continue
+		}
+
+		hit[target.Id] = true
+		target.Hp -= card.Damage
+
+		if target.Hp <= 0 {
+			m.remove(x, y)
+		}
+	}
+
+	m.checkCompleted()
+
+	return nil
+}
+
+// remove takes a dead entity off the board and out of the turn order.
+func (m *Match) remove(x int, y int) {
+	id := m.at(x, y).Id
+	m.encounter.Board.Entities.Entities[x].Entities[y] = &deviant.Entity{}
+
+	order := []string{}
+	for _, entityID := range m.encounter.ActiveEntityOrder {
+		if entityID != id {
+			order = append(order, entityID)
+		}
+	}
+
+	m.encounter.ActiveEntityOrder = order
+}
+
+// checkCompleted ends the match once at most one of the friendly and unfriendly alignments remains.
+func (m *Match) checkCompleted() {
+	alive := map[deviant.Alignment]bool{}
+
+	for _, row := range m.encounter.Board.Entities.Entities {
+		for _, entity := range row.Entities {
+			if entity.Id != "" {
+				alive[entity.Alignment] = true
+			}
+		}
+	}
+
+	switch {
+	case alive[deviant.Alignment_FRIENDLY] && alive[deviant.Alignment_UNFRIENDLY]:
+		return
+	case alive[deviant.Alignment_FRIENDLY]:
+		m.encounter.WinningAlignment = deviant.Alignment_FRIENDLY
+	case alive[deviant.Alignment_UNFRIENDLY]:
+		m.encounter.WinningAlignment = deviant.Alignment_UNFRIENDLY
+	default:
+		m.draw = true
+	}
+
+	m.encounter.Completed = true
+}
+
+// Stop ends the match without a winner, such as when it runs out of turns.
+func (m *Match) Stop() {
+	if m.encounter.Completed {
+		return
+	}
+
+	m.encounter.Completed = true
+	m.draw = true
+}
+
+// startTurn gives the turn to an entity, restoring its AP and drawing a card.
+func (m *Match) startTurn(id string) {
+	entity, _, _ := m.find(id)
+	entity.Ap = entity.MaxAp
+
+	if entity.Deck != nil && len(entity.Deck.Cards) == 0 && entity.Discard != nil {
+		entity.Deck.Cards, entity.Discard.Cards = entity.Discard.Cards, nil
+	}
+
+	if entity.Deck != nil && len(entity.Deck.Cards) > 0 {
+		if entity.Hand == nil {
+			entity.Hand = &deviant.Hand{}
+		}
+
+		entity.Hand.Cards = append(entity.Hand.Cards, entity.Deck.Cards[0])
+		entity.Deck.Cards = entity.Deck.Cards[1:]
+	}
+
+	m.encounter.ActiveEntity = entity
+	m.encounter.Turn = &deviant.Turn{Id: turnID(m.turns), Phase: deviant.TurnPhaseNames_PHASE_ACTION}
+}
+
+// EndTurn passes the turn to the next living entity in initiative order.
+func (m *Match) EndTurn() {
+	if m.encounter.Completed {
+		return
+	}
+
+	m.turns++
+
+	order := m.encounter.ActiveEntityOrder
+	next := 0
+	for i, id := range order {
+		if id == m.encounter.ActiveEntity.Id {
+			next = (i + 1) % len(order)
+			break
+		}
+	}
+
+	m.startTurn(order[next])
+}
@@ -0,0 +1,252 @@
+package sim
+
+import (
+	"testing"
+
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+)
+
+func generateCard(instanceID string, cost int32, damage int32) *deviant.Card {
+	return &deviant.Card{Id: "attack_slash_0000", InstanceId: instanceID, Cost: cost, Damage: damage}
+}
+
+// generateEncounter puts Ian and Ann on a 5x5 board facing Ben, with Ben first to act.
+func generateEncounter() *deviant.Encounter {
+	rows := []*deviant.EntitiesRow{}
+	for x := 0; x < 5; x++ {
+		row := &deviant.EntitiesRow{}
+		for y := 0; y < 5; y++ {
+			row.Entities = append(row.Entities, &deviant.Entity{})
+		}
+		rows = append(rows, row)
+	}
+
+	rows[0].Entities[0] = &deviant.Entity{
+		Id: "0001", OwnerId: "0001", Name: "Ian", Hp: 4, MaxAp: 3, Initiative: 2,
+		Alignment: deviant.Alignment_FRIENDLY,
+		Hand:      &deviant.Hand{Cards: []*deviant.Card{generateCard("slash_0000", 2, 3)}},
+		Deck:      &deviant.Deck{Cards: []*deviant.Card{generateCard("slash_0001", 2, 3)}},
+	}
+	rows[0].Entities[4] = &deviant.Entity{
+		Id: "0002", OwnerId: "0001", Name: "Ann", Hp: 4, MaxAp: 3, Initiative: 1,
+		Alignment: deviant.Alignment_FRIENDLY,
+	}
+	rows[4].Entities[0] = &deviant.Entity{
+		Id: "0003", OwnerId: "0002", Name: "Ben", Hp: 3, MaxAp: 3, Initiative: 5,
+		Alignment: deviant.Alignment_UNFRIENDLY,
+	}
+	// Rocks block tiles without taking turns.
+	rows[2].Entities[1] = &deviant.Entity{Id: "rock_0000", Hp: 99, Alignment: deviant.Alignment_NEUTRAL}
+
+	return &deviant.Encounter{
+		Id:    "encounter_0000",
+		Board: &deviant.Board{Entities: &deviant.Entities{Entities: rows}},
+	}
+}
+
+func move(x int32, y int32) *deviant.EncounterRequest {
+	return &deviant.EncounterRequest{
+		EntityActionName: deviant.EntityActionNames_MOVE,
+		EntityMoveAction: &deviant.EntityMoveAction{FinalXPosition: x, FinalYPosition: y},
+	}
+}
+
+func play(instanceID string, tiles ...[2]int32) *deviant.EncounterRequest {
+	plays := []*deviant.Play{}
+	for _, tile := range tiles {
+		plays = append(plays, &deviant.Play{X: tile[0], Y: tile[1]})
+	}
+
+	return &deviant.EncounterRequest{
+		EntityActionName: deviant.EntityActionNames_PLAY,
+		EntityPlayAction: &deviant.EntityPlayAction{CardId: instanceID, Plays: plays},
+	}
+}
+
+var endTurn = &deviant.EncounterRequest{EntityActionName: deviant.EntityActionNames_CHANGE_PHASE}
+
+func TestNew(t *testing.T) {
+	encounter := generateEncounter()
+
+	m, err := New(encounter)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if m.Active().Id != "0003" || m.Active().Ap != 3 {
+		t.Errorf("expected Ben to start with full ap but got %s with %d ap", m.Active().Id, m.Active().Ap)
+	}
+
+	if order := m.Encounter().ActiveEntityOrder; len(order) != 3 || order[0] != "0003" || order[1] != "0001" || order[2] != "0002" {
+		t.Errorf("expected entities in initiative order but got %v", order)
+	}
+
+	if encounter.ActiveEntity != nil {
+		t.Error("expected the encounter to be copied")
+	}
+
+	if _, err := New(&deviant.Encounter{}); err == nil {
+		t.Error("expected an error for an encounter without a board")
+	}
+}
+
+func TestNewKeepsTheActiveEntity(t *testing.T) {
+	encounter := generateEncounter()
+	encounter.Board.Entities.Entities[0].Entities[0].Ap = 1
+	encounter.ActiveEntity = &deviant.Entity{Id: "0001"}
+
+	m, err := New(encounter)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if m.Active().Id != "0001" || m.Active().Ap != 1 {
+		t.Errorf("expected Ian to keep the turn and his remaining ap but got %s with %d ap", m.Active().Id, m.Active().Ap)
+	}
+
+	encounter.ActiveEntity = &deviant.Entity{Id: "missing"}
+	if _, err := New(encounter); err == nil {
+		t.Error("expected an error for an active entity that is not on the board")
+	}
+}
+
+func TestMove(t *testing.T) {
+	m, _ := New(generateEncounter())
+
+	tests := map[string]*deviant.EncounterRequest{
+		"off the board": move(5, 0),
+		"occupied":      move(0, 0),
+		"out of reach":  move(0, 2),
+	}
+
+	for name, request := range tests {
+		if err := m.Apply(request); err == nil {
+			t.Errorf("%s: expected the move to be rejected", name)
+		}
+	}
+
+	if err := m.Apply(move(3, 1)); err != nil {
+		t.Fatal(err)
+	}
+
+	if ben := m.Encounter().Board.Entities.Entities[3].Entities[1]; ben.Id != "0003" || ben.Ap != 1 {
+		t.Errorf("expected Ben to move to (3, 1) with 1 ap left but got %s with %d ap", ben.Id, ben.Ap)
+	}
+
+	if m.Encounter().Board.Entities.Entities[4].Entities[0].Id != "" {
+		t.Error("expected Ben's old tile to be empty")
+	}
+}
+
+func TestMoveAroundObstacles(t *testing.T) {
+	encounter := generateEncounter()
+	rows := encounter.Board.Entities.Entities
+	rows[3].Entities[0] = &deviant.Entity{Id: "rock_0001", Hp: 99, Alignment: deviant.Alignment_NEUTRAL}
+	rows[4].Entities[1] = &deviant.Entity{Id: "rock_0002", Hp: 99, Alignment: deviant.Alignment_NEUTRAL}
+
+	m, _ := New(encounter)
+	if err := m.Apply(move(3, 1)); err == nil {
+		t.Error("expected Ben to be unable to move past the rocks")
+	}
+}
+
+func TestPlay(t *testing.T) {
+	m, _ := New(generateEncounter())
+	m.EndTurn()
+
+	if m.Active().Id != "0001" || len(m.Active().Hand.Cards) != 2 {
+		t.Fatalf("expected Ian to draw a card at the start of the turn")
+	}
+
+	if err := m.Apply(play("missing", [2]int32{4, 0})); err == nil {
+		t.Error("expected a card that is not in hand to be rejected")
+	}
+
+	if err := m.Apply(play("slash_0000", [2]int32{0, 4}, [2]int32{0, 4})); err != nil {
+		t.Fatal(err)
+	}
+
+	if ann := m.Encounter().Board.Entities.Entities[0].Entities[4]; ann.Hp != 1 {
+		t.Errorf("expected Ann to be hit once but has %d hp", ann.Hp)
+	}
+
+	if err := m.Apply(play("slash_0001", [2]int32{4, 0})); err == nil {
+		t.Error("expected a card costing more than the remaining ap to be rejected")
+	}
+
+	if ian := m.Active(); len(ian.Hand.Cards) != 1 || len(ian.Discard.Cards) != 1 || ian.Ap != 1 {
+		t.Errorf("expected the played card to be discarded and paid for but got %+v", ian)
+	}
+}
+
+func TestPlayEndsTheMatch(t *testing.T) {
+	m, _ := New(generateEncounter())
+	m.EndTurn()
+
+	if err := m.Apply(play("slash_0000", [2]int32{4, 0})); err != nil {
+		t.Fatal(err)
+	}
+
+	if !m.Completed() {
+		t.Fatal("expected the match to end once Ben is killed")
+	}
+
+	if winner, ok := m.Winner(); !ok || winner != deviant.Alignment_FRIENDLY {
+		t.Errorf("expected the friendly alignment to win but got %v", winner)
+	}
+
+	if m.Encounter().Board.Entities.Entities[4].Entities[0].Id != "" {
+		t.Error("expected Ben to leave the board")
+	}
+
+	if err := m.Apply(endTurn); err != ErrCompleted {
+		t.Errorf("expected requests after the match to be rejected but got %v", err)
+	}
+}
+
+func TestEndTurn(t *testing.T) {
+	m, _ := New(generateEncounter())
+
+	expected := []string{"0001", "0002", "0003", "0001"}
+	for i, id := range expected {
+		if err := m.Apply(endTurn); err != nil {
+			t.Fatal(err)
+		}
+
+		if m.Active().Id != id || m.Turns() != i+1 {
+			t.Fatalf("turn %d: expected %s to be active but got %s", m.Turns(), id, m.Active().Id)
+		}
+	}
+
+	// Ian drew his whole deck on his first turn, so he has nothing left to draw.
+	if ian := m.Active(); len(ian.Hand.Cards) != 2 || ian.Ap != ian.MaxAp {
+		t.Errorf("expected Ian to keep his hand and recover his ap but got %+v", ian)
+	}
+}
+
+func TestStop(t *testing.T) {
+	m, _ := New(generateEncounter())
+	m.Stop()
+
+	if !m.Completed() {
+		t.Fatal("expected a stopped match to be completed")
+	}
+
+	if _, ok := m.Winner(); ok {
+		t.Error("expected a stopped match to be a draw")
+	}
+}
+
+func TestResponse(t *testing.T) {
+	m, _ := New(generateEncounter())
+
+	response := m.Response("0002")
+	if response.PlayerId != "0002" || response.Encounter.ActiveEntity != response.Encounter.Board.Entities.Entities[4].Entities[0] {
+		t.Error("expected the response's active entity to be the one on the board")
+	}
+
+	response.Encounter.ActiveEntity.Hp = 0
+	if m.Active().Hp != 3 {
+		t.Error("expected the response to be a copy")
+	}
+}
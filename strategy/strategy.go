@@ -0,0 +1,136 @@
+package strategy
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/recluse-games/deviant-glados/hunting"
+	"github.com/recluse-games/deviant-glados/logging"
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+)
+
+// Default the name of the strategy used when none is selected.
+const Default = "hunting"
+
+// DefaultDifficulty the difficulty strategies play at unless another is chosen.
+const DefaultDifficulty = string(hunting.Normal)
+
+// Strategy plans the active entity's turn and explains the decision.
+type Strategy interface {
+	TakeTurn(encounterResponse *deviant.EncounterResponse, alignmentToHunt deviant.Alignment) ([]*deviant.EncounterRequest, *hunting.Trace)
+}
+
+// Func adapts a plain function to the Strategy interface.
+type Func func(encounterResponse *deviant.EncounterResponse, alignmentToHunt deviant.Alignment) ([]*deviant.EncounterRequest, *hunting.Trace)
+
+// TakeTurn calls f.
+func (f Func) TakeTurn(encounterResponse *deviant.EncounterResponse, alignmentToHunt deviant.Alignment) ([]*deviant.EncounterRequest, *hunting.Trace) {
+	return f(encounterResponse, alignmentToHunt)
+}
+
+// Tunable is implemented by strategies that can play at different difficulties. Any randomness in their play is
+// derived from seed, so that the same encounter and seed always plan the same turn.
+type Tunable interface {
+	Strategy
+	WithDifficulty(difficulty hunting.Difficulty, seed int64) Strategy
+}
+
+// Logged is implemented by strategies that can log how they plan. The returned strategy logs to logger.
+type Logged interface {
+	Strategy
+	WithLogger(logger logging.Logger) Strategy
+}
+
+// Hunting the hunting strategy tuned by planning options.
+type Hunting struct {
+	Options hunting.Options
+}
+
+// TakeTurn plans the turn with hunting.TakeTurnWithOptions.
+func (h Hunting) TakeTurn(encounterResponse *deviant.EncounterResponse, alignmentToHunt deviant.Alignment) ([]*deviant.EncounterRequest, *hunting.Trace) {
+	return hunting.TakeTurnWithOptions(encounterResponse, alignmentToHunt, &h.Options)
+}
+
+// WithLogger returns the hunting strategy logging how it plans to logger.
+func (h Hunting) WithLogger(logger logging.Logger) Strategy {
+	h.Options.Logger = logger
+
+	return h
+}
+
+// WithDifficulty returns the hunting strategy playing at difficulty.
+func (h Hunting) WithDifficulty(difficulty hunting.Difficulty, seed int64) Strategy {
+	options := difficulty.Options()
+	options.Seed = seed
+
+	return Hunting{Options: options}
+}
+
+var strategies = map[string]Strategy{
+	"hunting": Hunting{Options: hunting.Normal.Options()},
+}
+
+// Register makes a strategy available by name, replacing any strategy already registered under that name.
+func Register(name string, strategy Strategy) {
+	strategies[name] = strategy
+}
+
+// Get looks up a registered strategy by name.
+func Get(name string) (Strategy, error) {
+	strategy, ok := strategies[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown strategy %q, expected one of %v", name, Names())
+	}
+
+	return strategy, nil
+}
+
+// New looks up a registered strategy by name and tunes it to difficulty and seed. Empty names select Default and
+// DefaultDifficulty, and strategies that are not Tunable only play at DefaultDifficulty.
+func New(name string, difficulty string, seed int64) (Strategy, error) {
+	if name == "" {
+		name = Default
+	}
+
+	strategy, err := Get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	level, err := hunting.ParseDifficulty(difficulty)
+	if err != nil {
+		return nil, err
+	}
+
+	tunable, ok := strategy.(Tunable)
+	if !ok {
+		if string(level) != DefaultDifficulty {
+			return nil, fmt.Errorf("strategy %q does not support difficulty %q", name, level)
+		}
+
+		return strategy, nil
+	}
+
+	return tunable.WithDifficulty(level, seed), nil
+}
+
+// Names returns the registered strategy names in sorted order.
+func Names() []string {
+	names := []string{}
+	for name := range strategies {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// OpposingAlignment returns the alignment an entity of the given alignment hunts.
+func OpposingAlignment(alignment deviant.Alignment) deviant.Alignment {
+	if alignment == deviant.Alignment_FRIENDLY {
+		return deviant.Alignment_UNFRIENDLY
+	}
+
+	return deviant.Alignment_FRIENDLY
+}
@@ -0,0 +1,104 @@
+package strategy
+
+import (
+	"testing"
+
+	"github.com/recluse-games/deviant-glados/hunting"
+	"github.com/recluse-games/deviant-glados/logging"
+	deviant "github.com/recluse-games/deviant-protobuf/genproto/go"
+)
+
+func TestGet(t *testing.T) {
+	if _, err := Get(Default); err != nil {
+		t.Fatalf("expected the default strategy to be registered: %v", err)
+	}
+
+	if _, err := Get("missing"); err == nil {
+		t.Fatal("expected an error for an unknown strategy")
+	}
+}
+
+func TestRegister(t *testing.T) {
+	called := false
+	Register("test", Func(func(encounterResponse *deviant.EncounterResponse, alignmentToHunt deviant.Alignment) ([]*deviant.EncounterRequest, *hunting.Trace) {
+		called = true
+		return nil, nil
+	}))
+	defer delete(strategies, "test")
+
+	registered, err := Get("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	registered.TakeTurn(&deviant.EncounterResponse{}, deviant.Alignment_FRIENDLY)
+	if !called {
+		t.Fatal("expected the registered strategy to be called")
+	}
+}
+
+func TestOpposingAlignment(t *testing.T) {
+	if OpposingAlignment(deviant.Alignment_FRIENDLY) != deviant.Alignment_UNFRIENDLY {
+		t.Error("friendly entities should hunt unfriendly ones")
+	}
+
+	if OpposingAlignment(deviant.Alignment_UNFRIENDLY) != deviant.Alignment_FRIENDLY {
+		t.Error("unfriendly entities should hunt friendly ones")
+	}
+}
+
+func TestNew(t *testing.T) {
+	for _, difficulty := range []string{"", "easy", "nightmare"} {
+		s, err := New("", difficulty, 7)
+		if err != nil {
+			t.Fatalf("%q: %v", difficulty, err)
+		}
+
+		level, _ := hunting.ParseDifficulty(difficulty)
+		expected := level.Options()
+		expected.Seed = 7
+
+		if tuned, ok := s.(Hunting); !ok || tuned.Options != expected {
+			t.Errorf("%q: expected the hunting strategy with %+v but got %+v", difficulty, expected, s)
+		}
+	}
+
+	if _, err := New("", "impossible", 0); err == nil {
+		t.Error("expected an error for an unknown difficulty")
+	}
+
+	if _, err := New("missing", "", 0); err == nil {
+		t.Error("expected an error for an unknown strategy")
+	}
+}
+
+func TestHuntingWithLogger(t *testing.T) {
+	s, err := New("", "easy", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logger := logging.Nop()
+	logged, ok := s.(Logged)
+	if !ok {
+		t.Fatal("expected the hunting strategy to support logging")
+	}
+
+	tuned := logged.WithLogger(logger).(Hunting)
+	if tuned.Options.Logger != logger || tuned.Options.Seed != 3 || s.(Hunting).Options.Logger != nil {
+		t.Fatalf("expected a copy logging to the logger with the same options, got %+v", tuned.Options)
+	}
+}
+
+func TestNewWithoutDifficulties(t *testing.T) {
+	Register("test", Func(hunting.TakeTurnWithTrace))
+	defer delete(strategies, "test")
+
+	if _, err := New("test", DefaultDifficulty, 0); err != nil {
+		t.Fatalf("expected strategies to play at the default difficulty: %v", err)
+	}
+
+	if _, err := New("test", "hard", 0); err == nil {
+		t.Error("expected an error for a strategy that cannot be tuned")
+	}
+}